@@ -0,0 +1,636 @@
+// Package ansi parses streams of text containing ANSI escape sequences into typed tokens.
+//
+// The parser implements the state machine of the DEC VT500 series terminals, as described by
+// Paul Williams at https://vt100.net/emu/dec_ansi_parser, so it handles input the same way
+// a real terminal would: including malformed or unterminated sequences, control characters
+// in the middle of a sequence and string terminators of either BEL or ESC \.
+//
+//	for _, tok := range ansi.Parse("\x1b[1;32mok\x1b[0m\n") {
+//		fmt.Println(tok.Kind, strconv.Quote(tok.Raw))
+//	}
+//
+// Prints:
+//
+//	SGR "\x1b[1;32m"
+//	Text "ok"
+//	SGR "\x1b[0m"
+//	Control "\n"
+//
+// The parser works on UTF-8 text, so bytes 0x80 and above are always treated as text rather
+// than the 8 bit C1 control characters of the original terminals.
+package ansi // import "go.followtheprocess.codes/hue/ansi"
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	esc = 0x1b // Escape, begins every escape sequence
+	bel = 0x07 // Bell, terminates an OSC string (as well as ESC \)
+	can = 0x18 // Cancel, aborts any sequence in progress
+	sub = 0x1a // Substitute, aborts any sequence in progress
+	del = 0x7f // Delete, ignored within sequences
+
+	maxParams = 32    // Most parameters kept for a single sequence, as the VT500 did
+	maxParam  = 65535 // Largest value of a single parameter, larger values are clamped
+)
+
+// Kind is the kind of a [Token].
+type Kind int
+
+const (
+	Text    Kind = iota // Printable text
+	Control             // A C0 control character e.g. '\n', '\r' or '\b'
+	Escape              // An escape sequence other than the kinds below e.g. "ESC 7" to save the cursor
+	CSI                 // A control sequence e.g. to move the cursor or erase the line, other than SGR
+	SGR                 // A control sequence setting graphic rendition (styles), with its Attrs decoded
+	OSC                 // An operating system command e.g. a hyperlink or window title
+	DCS                 // A device control string
+	Ignored             // A malformed or cancelled sequence, or a SOS, PM or APC string, which terminals ignore
+)
+
+// String implements [fmt.Stringer] for a [Kind].
+func (k Kind) String() string {
+	switch k {
+	case Text:
+		return "Text"
+	case Control:
+		return "Control"
+	case Escape:
+		return "Escape"
+	case CSI:
+		return "CSI"
+	case SGR:
+		return "SGR"
+	case OSC:
+		return "OSC"
+	case DCS:
+		return "DCS"
+	case Ignored:
+		return "Ignored"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Token is a single token of parsed input.
+//
+// Concatenating the Raw text of every token gives back the input, except that control characters
+// embedded within a sequence (which a terminal acts on immediately) are given before it.
+type Token struct {
+	Raw           string // The bytes of the token, exactly as they appeared in the input
+	Data          string // The payload of an OSC or DCS string, without the introducer or terminator
+	Intermediates string // Intermediate bytes of an Escape, CSI or DCS sequence, including any private marker e.g. "?" in "ESC [ ? 25 h"
+	Params        []int  // Numeric parameters of a CSI or DCS sequence, omitted parameters are 0
+	Attrs         []Attr // The attributes set by an SGR sequence
+	Kind          Kind   // What kind of token it is
+	Final         byte   // Final byte of an Escape, CSI or DCS sequence, or the character of a Control token
+}
+
+// Param returns the ith parameter of the token, or def if it was omitted (or 0, which
+// terminals treat the same way).
+func (t Token) Param(i, def int) int {
+	if i >= len(t.Params) || t.Params[i] == 0 {
+		return def
+	}
+
+	return t.Params[i]
+}
+
+// State is a state of the parser's state machine.
+type State int
+
+const (
+	StateGround             State = iota // Not in a sequence, printing text
+	StateEscape                          // After an ESC
+	StateEscapeIntermediate              // After intermediate bytes of an escape sequence
+	StateCSIEntry                        // After ESC [
+	StateCSIParam                        // In the parameters of a control sequence
+	StateCSIIntermediate                 // After intermediate bytes of a control sequence
+	StateCSIIgnore                       // In a malformed control sequence, ignoring until its final byte
+	StateDCSEntry                        // After ESC P
+	StateDCSParam                        // In the parameters of a device control string
+	StateDCSIntermediate                 // After intermediate bytes of a device control string
+	StateDCSPassthrough                  // In the data of a device control string
+	StateDCSIgnore                       // In a malformed device control string, ignoring until its terminator
+	StateOSCString                       // In an operating system command
+	StateSOSPMAPCString                  // In a SOS, PM or APC string, ignoring until its terminator
+)
+
+// String implements [fmt.Stringer] for a [State].
+func (s State) String() string {
+	switch s {
+	case StateGround:
+		return "Ground"
+	case StateEscape:
+		return "Escape"
+	case StateEscapeIntermediate:
+		return "EscapeIntermediate"
+	case StateCSIEntry:
+		return "CSIEntry"
+	case StateCSIParam:
+		return "CSIParam"
+	case StateCSIIntermediate:
+		return "CSIIntermediate"
+	case StateCSIIgnore:
+		return "CSIIgnore"
+	case StateDCSEntry:
+		return "DCSEntry"
+	case StateDCSParam:
+		return "DCSParam"
+	case StateDCSIntermediate:
+		return "DCSIntermediate"
+	case StateDCSPassthrough:
+		return "DCSPassthrough"
+	case StateDCSIgnore:
+		return "DCSIgnore"
+	case StateOSCString:
+		return "OSCString"
+	case StateSOSPMAPCString:
+		return "SOSPMAPCString"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Parse parses s, returning its tokens.
+//
+// An unterminated sequence at the end of s is returned as an [Ignored] token.
+func Parse(s string) []Token {
+	var tokens []Token
+
+	p := &Parser{Handler: func(tok Token) { tokens = append(tokens, tok) }}
+	p.WriteString(s) //nolint: errcheck // Parsing never fails
+	p.Flush()
+
+	return tokens
+}
+
+// Strip returns s with every escape sequence removed, leaving the text and
+// control characters.
+func Strip(s string) string {
+	var b []byte
+
+	p := &Parser{Handler: func(tok Token) {
+		if tok.Kind == Text || tok.Kind == Control {
+			b = append(b, tok.Raw...)
+		}
+	}}
+	p.WriteString(s) //nolint: errcheck // Parsing never fails
+	p.Flush()
+
+	return string(b)
+}
+
+// Width returns the width of s as shown on a terminal, that is the number of runes of text
+// in s excluding any escape sequences or control characters.
+//
+// Like [text/tabwriter], it assumes every rune has a width of 1.
+func Width(s string) int {
+	width := 0
+
+	p := &Parser{Handler: func(tok Token) {
+		if tok.Kind == Text {
+			width += utf8.RuneCountInString(tok.Raw)
+		}
+	}}
+	p.WriteString(s) //nolint: errcheck // Parsing never fails
+	p.Flush()
+
+	return width
+}
+
+// Parser is a streaming parser for text containing ANSI escape sequences, input written to it
+// is parsed into tokens which are passed to its Handler as they are completed.
+//
+// Sequences, and UTF-8 encoded runes of text, may be split across calls to Write, callers must
+// call [Parser.Flush] when done writing to ensure any final text is handled.
+//
+// If Handler is nil, the parser only tracks its [State], which it does without allocating:
+// this is useful to find where each escape sequence ends.
+//
+// The zero value is ready to use. A Parser must not be copied after first use.
+type Parser struct {
+	Handler func(Token) // Called with each token as it is parsed
+
+	text   []byte // Text not yet passed to the handler
+	raw    []byte // Raw bytes of the current sequence
+	data   []byte // Payload of the current OSC or DCS string
+	inter  []byte // Intermediate bytes of the current sequence
+	params []int  // Parameters of the current sequence
+	param  int    // The parameter currently being parsed
+	final  byte   // Final byte of the current DCS sequence
+	state  State  // Current state of the state machine
+	digits bool   // Whether the current sequence has any parameter bytes
+	escape bool   // Whether an ESC has been seen in a string, which may be the start of a string terminator
+}
+
+// State returns the current state of the parser. It is [StateGround] whenever the parser
+// is not in the middle of a sequence.
+func (p *Parser) State() State {
+	return p.state
+}
+
+// Write parses data, it never returns an error.
+func (p *Parser) Write(data []byte) (n int, err error) {
+	for _, b := range data {
+		p.advance(b)
+	}
+
+	p.flushText(false)
+
+	return len(data), nil
+}
+
+// WriteString is like [Parser.Write] but takes a string.
+func (p *Parser) WriteString(s string) (n int, err error) {
+	for i := range len(s) {
+		p.advance(s[i])
+	}
+
+	p.flushText(false)
+
+	return len(s), nil
+}
+
+// WriteByte parses a single byte, it never returns an error.
+//
+// Text is not passed to the handler until a byte that isn't text is written, or a call to
+// [Parser.Write] or [Parser.Flush].
+func (p *Parser) WriteByte(b byte) error {
+	p.advance(b)
+	return nil
+}
+
+// Flush passes any remaining text to the handler, along with any unterminated sequence
+// as an [Ignored] token, and resets the parser to [StateGround].
+func (p *Parser) Flush() {
+	p.flushText(true)
+
+	if p.state != StateGround || p.escape {
+		p.abort()
+	}
+}
+
+// advance moves the state machine on by one byte of input.
+func (p *Parser) advance(b byte) {
+	if p.escape {
+		// An ESC in a string: either the string terminator ESC \ or the string was ended by
+		// the start of the next sequence
+		p.escape = false
+		if b == '\\' {
+			p.collect(b)
+			p.endString()
+
+			return
+		}
+
+		if len(p.raw) > 0 {
+			p.raw = p.raw[:len(p.raw)-1]
+		}
+
+		p.endString()
+		p.begin(esc)
+	}
+
+	// Transitions from anywhere
+	switch b {
+	case can, sub:
+		if p.state != StateGround {
+			p.abort()
+		}
+
+		p.execute(b)
+
+		return
+	case esc:
+		switch p.state {
+		case StateOSCString, StateDCSPassthrough, StateDCSIgnore, StateSOSPMAPCString:
+			p.collect(b)
+			p.escape = true
+		default:
+			if p.state != StateGround {
+				p.abort()
+			}
+
+			p.begin(b)
+		}
+
+		return
+	}
+
+	if b >= 0x80 && p.state != StateGround && !p.inString() {
+		// UTF-8 text can't be part of a sequence, abandon it
+		p.abort()
+	}
+
+	switch p.state {
+	case StateGround:
+		if b < 0x20 {
+			p.execute(b)
+			return
+		}
+
+		if p.Handler != nil {
+			p.text = append(p.text, b)
+		}
+	case StateEscape:
+		p.collect(b)
+
+		switch {
+		case b < 0x20:
+			p.executeWithin(b)
+		case b <= 0x2f:
+			p.intermediate(b)
+			p.state = StateEscapeIntermediate
+		case b == '[':
+			p.state = StateCSIEntry
+		case b == ']':
+			p.state = StateOSCString
+		case b == 'P':
+			p.state = StateDCSEntry
+		case b == 'X' || b == '^' || b == '_':
+			p.state = StateSOSPMAPCString
+		case b == del:
+		default:
+			p.dispatch(Escape, b)
+		}
+	case StateEscapeIntermediate:
+		p.collect(b)
+
+		switch {
+		case b < 0x20:
+			p.executeWithin(b)
+		case b <= 0x2f:
+			p.intermediate(b)
+		case b == del:
+		default:
+			p.dispatch(Escape, b)
+		}
+	case StateCSIEntry, StateCSIParam, StateCSIIntermediate, StateCSIIgnore:
+		p.collect(b)
+		p.csi(b)
+	case StateDCSEntry, StateDCSParam, StateDCSIntermediate:
+		p.collect(b)
+		p.dcs(b)
+	case StateDCSPassthrough:
+		p.collect(b)
+
+		if b != del && p.Handler != nil {
+			p.data = append(p.data, b)
+		}
+	case StateOSCString:
+		p.collect(b)
+
+		switch {
+		case b == bel:
+			p.endString()
+		case b < 0x20:
+		default:
+			if p.Handler != nil {
+				p.data = append(p.data, b)
+			}
+		}
+	case StateDCSIgnore, StateSOSPMAPCString:
+		p.collect(b)
+	}
+}
+
+// csi handles a byte (already collected) in one of the CSI states.
+func (p *Parser) csi(b byte) {
+	switch {
+	case b < 0x20:
+		p.executeWithin(b)
+	case b == del:
+	case b >= 0x40:
+		if p.state == StateCSIIgnore {
+			p.abort()
+		} else {
+			p.dispatch(CSI, b)
+		}
+	case p.state == StateCSIIgnore:
+	case b <= 0x2f:
+		p.intermediate(b)
+		p.state = StateCSIIntermediate
+	case b <= 0x3b && p.state != StateCSIIntermediate:
+		p.parameter(b)
+		p.state = StateCSIParam
+	case b >= 0x3c && p.state == StateCSIEntry:
+		// Private marker e.g. '?'
+		p.intermediate(b)
+		p.state = StateCSIParam
+	default:
+		p.state = StateCSIIgnore
+	}
+}
+
+// dcs handles a byte (already collected) in one of the DCS states before the passthrough.
+func (p *Parser) dcs(b byte) {
+	switch {
+	case b < 0x20, b == del:
+	case b >= 0x40:
+		p.final = b
+		p.state = StateDCSPassthrough
+	case b <= 0x2f:
+		p.intermediate(b)
+		p.state = StateDCSIntermediate
+	case b <= 0x3b && p.state != StateDCSIntermediate:
+		p.parameter(b)
+		p.state = StateDCSParam
+	case b >= 0x3c && p.state == StateDCSEntry:
+		p.intermediate(b)
+		p.state = StateDCSParam
+	default:
+		p.state = StateDCSIgnore
+	}
+}
+
+// inString reports whether the parser is in one of the string states, where any byte
+// may appear until the string terminator.
+func (p *Parser) inString() bool {
+	switch p.state {
+	case StateOSCString, StateDCSPassthrough, StateDCSIgnore, StateSOSPMAPCString:
+		return true
+	default:
+		return false
+	}
+}
+
+// begin starts a new escape sequence with the ESC b.
+func (p *Parser) begin(b byte) {
+	p.flushText(true)
+
+	p.raw = p.raw[:0]
+	p.data = p.data[:0]
+	p.inter = p.inter[:0]
+	p.params = p.params[:0]
+	p.param = 0
+	p.final = 0
+	p.digits = false
+	p.state = StateEscape
+
+	p.collect(b)
+}
+
+// collect adds b to the raw bytes of the current sequence.
+func (p *Parser) collect(b byte) {
+	if p.Handler != nil {
+		p.raw = append(p.raw, b)
+	}
+}
+
+// intermediate records b as an intermediate byte of the current sequence.
+func (p *Parser) intermediate(b byte) {
+	if p.Handler != nil {
+		p.inter = append(p.inter, b)
+	}
+}
+
+// parameter handles b, a digit or separator, in the parameters of the current sequence.
+func (p *Parser) parameter(b byte) {
+	if p.Handler == nil {
+		return
+	}
+
+	p.digits = true
+
+	if b == ';' || b == ':' {
+		if len(p.params) < maxParams {
+			p.params = append(p.params, p.param)
+		}
+
+		p.param = 0
+
+		return
+	}
+
+	p.param = min(p.param*10+int(b-'0'), maxParam) //nolint: mnd // Decimal
+}
+
+// execute handles a control character outside of a sequence.
+func (p *Parser) execute(b byte) {
+	p.flushText(true)
+
+	if p.Handler != nil {
+		p.Handler(Token{Kind: Control, Raw: string(b), Final: b})
+	}
+}
+
+// executeWithin handles a control character (already collected) in the middle of a
+// sequence, which a terminal acts on immediately without affecting the sequence.
+func (p *Parser) executeWithin(b byte) {
+	if p.Handler == nil {
+		return
+	}
+
+	p.raw = p.raw[:len(p.raw)-1]
+	p.Handler(Token{Kind: Control, Raw: string(b), Final: b})
+}
+
+// dispatch completes the current escape or control sequence with its final byte.
+func (p *Parser) dispatch(kind Kind, final byte) {
+	p.state = StateGround
+
+	if p.Handler == nil {
+		return
+	}
+
+	tok := Token{
+		Kind:          kind,
+		Raw:           string(p.raw),
+		Intermediates: string(p.inter),
+		Final:         final,
+		Params:        p.finishParams(),
+	}
+
+	if kind == CSI && final == 'm' && len(p.inter) == 0 {
+		tok.Kind = SGR
+		tok.Attrs = decodeSGR(tok.Raw[2 : len(tok.Raw)-1])
+	}
+
+	p.Handler(tok)
+}
+
+// endString completes the current string sequence, after its terminator.
+func (p *Parser) endString() {
+	state := p.state
+	p.state = StateGround
+
+	if p.Handler == nil {
+		return
+	}
+
+	tok := Token{Kind: Ignored, Raw: string(p.raw)}
+
+	switch state {
+	case StateOSCString:
+		tok.Kind = OSC
+		tok.Data = string(p.data)
+	case StateDCSPassthrough:
+		tok.Kind = DCS
+		tok.Data = string(p.data)
+		tok.Intermediates = string(p.inter)
+		tok.Params = p.finishParams()
+		tok.Final = p.final
+	}
+
+	p.Handler(tok)
+}
+
+// abort abandons the current sequence, passing what there is of it to the handler
+// as an Ignored token.
+func (p *Parser) abort() {
+	p.state = StateGround
+	p.escape = false
+
+	if p.Handler != nil && len(p.raw) > 0 {
+		p.Handler(Token{Kind: Ignored, Raw: string(p.raw)})
+	}
+
+	p.raw = p.raw[:0]
+}
+
+// finishParams returns the parameters of the current sequence, including the last.
+func (p *Parser) finishParams() []int {
+	if !p.digits {
+		return nil
+	}
+
+	params := p.params
+	if len(params) < maxParams {
+		params = append(params, p.param)
+	}
+
+	return append([]int(nil), params...)
+}
+
+// flushText passes any pending text to the handler. Unless all is true, an incomplete
+// UTF-8 encoded rune at the end of the text is kept back to be completed by later input.
+func (p *Parser) flushText(all bool) {
+	if len(p.text) == 0 {
+		return
+	}
+
+	n := len(p.text)
+	if !all {
+		// Look back for the start of the last rune
+		start := max(n-utf8.UTFMax, 0)
+		for i := n - 1; i >= start; i-- {
+			if utf8.RuneStart(p.text[i]) {
+				if !utf8.FullRune(p.text[i:]) {
+					n = i
+				}
+
+				break
+			}
+		}
+	}
+
+	if n == 0 {
+		return
+	}
+
+	p.Handler(Token{Kind: Text, Raw: string(p.text[:n])})
+	p.text = append(p.text[:0], p.text[n:]...)
+}
@@ -0,0 +1,368 @@
+package ansi_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue/ansi"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string       // Name of the test case
+		input string       // Input to parse
+		want  []ansi.Token // Expected tokens
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "text",
+			input: "hello, 世界",
+			want:  []ansi.Token{{Kind: ansi.Text, Raw: "hello, 世界"}},
+		},
+		{
+			name:  "control",
+			input: "a\r\nb",
+			want: []ansi.Token{
+				{Kind: ansi.Text, Raw: "a"},
+				{Kind: ansi.Control, Raw: "\r", Final: '\r'},
+				{Kind: ansi.Control, Raw: "\n", Final: '\n'},
+				{Kind: ansi.Text, Raw: "b"},
+			},
+		},
+		{
+			name:  "sgr",
+			input: "\x1b[1;32mok\x1b[m",
+			want: []ansi.Token{
+				{
+					Kind:   ansi.SGR,
+					Raw:    "\x1b[1;32m",
+					Final:  'm',
+					Params: []int{1, 32},
+					Attrs: []ansi.Attr{
+						{Kind: ansi.Bold},
+						{Kind: ansi.Foreground, Colour: ansi.Colour{Type: ansi.Basic, Index: 2}},
+					},
+				},
+				{Kind: ansi.Text, Raw: "ok"},
+				{Kind: ansi.SGR, Raw: "\x1b[m", Final: 'm', Attrs: []ansi.Attr{{Kind: ansi.Reset}}},
+			},
+		},
+		{
+			name:  "csi",
+			input: "\x1b[2A\x1b[;5H\x1b[?25l",
+			want: []ansi.Token{
+				{Kind: ansi.CSI, Raw: "\x1b[2A", Final: 'A', Params: []int{2}},
+				{Kind: ansi.CSI, Raw: "\x1b[;5H", Final: 'H', Params: []int{0, 5}},
+				{Kind: ansi.CSI, Raw: "\x1b[?25l", Final: 'l', Params: []int{25}, Intermediates: "?"},
+			},
+		},
+		{
+			name:  "escape",
+			input: "\x1b7\x1b(B",
+			want: []ansi.Token{
+				{Kind: ansi.Escape, Raw: "\x1b7", Final: '7'},
+				{Kind: ansi.Escape, Raw: "\x1b(B", Final: 'B', Intermediates: "("},
+			},
+		},
+		{
+			name:  "osc",
+			input: "\x1b]8;;https://go.dev\x1b\\Go\x1b]8;;\a",
+			want: []ansi.Token{
+				{Kind: ansi.OSC, Raw: "\x1b]8;;https://go.dev\x1b\\", Data: "8;;https://go.dev"},
+				{Kind: ansi.Text, Raw: "Go"},
+				{Kind: ansi.OSC, Raw: "\x1b]8;;\a", Data: "8;;"},
+			},
+		},
+		{
+			name:  "osc ended by next sequence",
+			input: "\x1b]0;title\x1b[1m",
+			want: []ansi.Token{
+				{Kind: ansi.OSC, Raw: "\x1b]0;title", Data: "0;title"},
+				{Kind: ansi.SGR, Raw: "\x1b[1m", Final: 'm', Params: []int{1}, Attrs: []ansi.Attr{{Kind: ansi.Bold}}},
+			},
+		},
+		{
+			name:  "dcs",
+			input: "\x1bP1$rdata\x1b\\",
+			want: []ansi.Token{
+				{Kind: ansi.DCS, Raw: "\x1bP1$rdata\x1b\\", Data: "data", Params: []int{1}, Intermediates: "$", Final: 'r'},
+			},
+		},
+		{
+			name:  "apc",
+			input: "\x1b_ignored\x1b\\x",
+			want: []ansi.Token{
+				{Kind: ansi.Ignored, Raw: "\x1b_ignored\x1b\\"},
+				{Kind: ansi.Text, Raw: "x"},
+			},
+		},
+		{
+			name:  "control within sequence",
+			input: "\x1b[1\n2m",
+			want: []ansi.Token{
+				{Kind: ansi.Control, Raw: "\n", Final: '\n'},
+				{Kind: ansi.SGR, Raw: "\x1b[12m", Final: 'm', Params: []int{12}, Attrs: []ansi.Attr{{Kind: ansi.Unknown, Code: 12}}},
+			},
+		},
+		{
+			name:  "cancelled",
+			input: "\x1b[12\x18x",
+			want: []ansi.Token{
+				{Kind: ansi.Ignored, Raw: "\x1b[12"},
+				{Kind: ansi.Control, Raw: "\x18", Final: 0x18},
+				{Kind: ansi.Text, Raw: "x"},
+			},
+		},
+		{
+			name:  "malformed csi",
+			input: "\x1b[1?2mx",
+			want: []ansi.Token{
+				{Kind: ansi.Ignored, Raw: "\x1b[1?2m"},
+				{Kind: ansi.Text, Raw: "x"},
+			},
+		},
+		{
+			name:  "escape restarts sequence",
+			input: "\x1b[1\x1b[2m",
+			want: []ansi.Token{
+				{Kind: ansi.Ignored, Raw: "\x1b[1"},
+				{Kind: ansi.SGR, Raw: "\x1b[2m", Final: 'm', Params: []int{2}, Attrs: []ansi.Attr{{Kind: ansi.Dim}}},
+			},
+		},
+		{
+			name:  "unterminated",
+			input: "x\x1b[31",
+			want: []ansi.Token{
+				{Kind: ansi.Text, Raw: "x"},
+				{Kind: ansi.Ignored, Raw: "\x1b[31"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ansi.Parse(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSGR(t *testing.T) {
+	tests := []struct {
+		name   string      // Name of the test case
+		params string      // Parameters of the SGR sequence
+		want   []ansi.Attr // Expected attributes
+	}{
+		{
+			name:   "modifiers",
+			params: "0;1;2;3;4;5;7;8;9;22;23;24;25;27;28;29",
+			want: []ansi.Attr{
+				{Kind: ansi.Reset}, {Kind: ansi.Bold}, {Kind: ansi.Dim}, {Kind: ansi.Italic},
+				{Kind: ansi.Underline}, {Kind: ansi.Blink}, {Kind: ansi.Reverse}, {Kind: ansi.Hidden},
+				{Kind: ansi.Strikethrough}, {Kind: ansi.NormalIntensity}, {Kind: ansi.NotItalic},
+				{Kind: ansi.NotUnderline}, {Kind: ansi.NotBlink}, {Kind: ansi.NotReverse},
+				{Kind: ansi.NotHidden}, {Kind: ansi.NotStrikethrough},
+			},
+		},
+		{
+			name:   "basic colours",
+			params: "31;44;97;100;39;49",
+			want: []ansi.Attr{
+				{Kind: ansi.Foreground, Colour: ansi.Colour{Type: ansi.Basic, Index: 1}},
+				{Kind: ansi.Background, Colour: ansi.Colour{Type: ansi.Basic, Index: 4}},
+				{Kind: ansi.Foreground, Colour: ansi.Colour{Type: ansi.Basic, Index: 15}},
+				{Kind: ansi.Background, Colour: ansi.Colour{Type: ansi.Basic, Index: 8}},
+				{Kind: ansi.DefaultForeground},
+				{Kind: ansi.DefaultBackground},
+			},
+		},
+		{
+			name:   "extended colours",
+			params: "38;5;208;48;2;1;2;3;1",
+			want: []ansi.Attr{
+				{Kind: ansi.Foreground, Colour: ansi.Colour{Type: ansi.Indexed, Index: 208}},
+				{Kind: ansi.Background, Colour: ansi.Colour{Type: ansi.TrueColour, R: 1, G: 2, B: 3}},
+				{Kind: ansi.Bold},
+			},
+		},
+		{
+			name:   "colon colours",
+			params: "38:2::255:136:0;58:5:1;48:2:9:8:7",
+			want: []ansi.Attr{
+				{Kind: ansi.Foreground, Colour: ansi.Colour{Type: ansi.TrueColour, R: 255, G: 136}},
+				{Kind: ansi.UnderlineColour, Colour: ansi.Colour{Type: ansi.Indexed, Index: 1}},
+				{Kind: ansi.Background, Colour: ansi.Colour{Type: ansi.TrueColour, R: 9, G: 8, B: 7}},
+			},
+		},
+		{
+			name:   "invalid colours",
+			params: "38;5;300;1;48;2;1",
+			want: []ansi.Attr{
+				{Kind: ansi.Unknown, Code: 38},
+				{Kind: ansi.Bold},
+				{Kind: ansi.Unknown, Code: 48},
+			},
+		},
+		{
+			name:   "unknown",
+			params: "53",
+			want:   []ansi.Attr{{Kind: ansi.Unknown, Code: 53}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := ansi.Parse("\x1b[" + tt.params + "m")
+			if len(tokens) != 1 || tokens[0].Kind != ansi.SGR {
+				t.Fatalf("expected a single SGR token, got %+v", tokens)
+			}
+
+			if got := tokens[0].Attrs; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParserStreaming(t *testing.T) {
+	input := "\x1b[1;32m世界\x1b]8;;https://go.dev\x1b\\link\x1b]8;;\x1b\\\r\n"
+	want := ansi.Parse(input)
+
+	// Write a byte at a time, splitting every sequence and rune
+	var got []ansi.Token
+
+	p := &ansi.Parser{Handler: func(tok ansi.Token) { got = append(got, tok) }}
+	for i := range len(input) {
+		p.Write([]byte{input[i]})
+	}
+
+	p.Flush()
+
+	// Text may be split differently, so join adjacent text before comparing
+	if !reflect.DeepEqual(joinText(got), joinText(want)) {
+		t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, want)
+	}
+
+	for _, tok := range got {
+		if tok.Kind == ansi.Text && !strings.HasPrefix(tok.Raw, "世") && strings.ContainsRune(tok.Raw, '�') {
+			t.Errorf("rune split across text tokens: %q", tok.Raw)
+		}
+	}
+}
+
+func TestParserState(t *testing.T) {
+	p := &ansi.Parser{}
+
+	steps := []struct {
+		input byte
+		want  ansi.State
+	}{
+		{'a', ansi.StateGround},
+		{0x1b, ansi.StateEscape},
+		{'[', ansi.StateCSIEntry},
+		{'?', ansi.StateCSIParam},
+		{'2', ansi.StateCSIParam},
+		{' ', ansi.StateCSIIntermediate},
+		{'1', ansi.StateCSIIgnore},
+		{'q', ansi.StateGround},
+		{0x1b, ansi.StateEscape},
+		{'P', ansi.StateDCSEntry},
+		{'1', ansi.StateDCSParam},
+		{'|', ansi.StateDCSPassthrough},
+		{0x1b, ansi.StateDCSPassthrough},
+		{'\\', ansi.StateGround},
+		{0x1b, ansi.StateEscape},
+		{']', ansi.StateOSCString},
+		{0x07, ansi.StateGround},
+		{0x1b, ansi.StateEscape},
+		{'#', ansi.StateEscapeIntermediate},
+		{'8', ansi.StateGround},
+	}
+
+	for i, step := range steps {
+		p.WriteByte(step.input)
+
+		if got := p.State(); got != step.want {
+			t.Fatalf("step %d (%q): state = %s, wanted %s", i, step.input, got, step.want)
+		}
+	}
+}
+
+func TestStripWidth(t *testing.T) {
+	tests := []struct {
+		input string // Input text
+		strip string // Expected result of Strip
+		width int    // Expected result of Width
+	}{
+		{input: "plain", strip: "plain", width: 5},
+		{input: "\x1b[1;32m世界\x1b[0m", strip: "世界", width: 2},
+		{input: "\x1b]8;;https://go.dev\x1b\\Go\x1b]8;;\x1b\\", strip: "Go", width: 2},
+		{input: "\x1b[2K\rline\n", strip: "\rline\n", width: 4},
+		{input: "\x1b[31", strip: "", width: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Quote(tt.input), func(t *testing.T) {
+			if got := ansi.Strip(tt.input); got != tt.strip {
+				t.Errorf("Strip(%q) = %q, wanted %q", tt.input, got, tt.strip)
+			}
+
+			if got := ansi.Width(tt.input); got != tt.width {
+				t.Errorf("Width(%q) = %d, wanted %d", tt.input, got, tt.width)
+			}
+		})
+	}
+}
+
+func TestParam(t *testing.T) {
+	tok := ansi.Parse("\x1b[0;5H")[0]
+
+	if got := tok.Param(0, 1); got != 1 {
+		t.Errorf("Param(0, 1) = %d, wanted 1", got)
+	}
+
+	if got := tok.Param(1, 1); got != 5 {
+		t.Errorf("Param(1, 1) = %d, wanted 5", got)
+	}
+
+	if got := tok.Param(2, 1); got != 1 {
+		t.Errorf("Param(2, 1) = %d, wanted 1", got)
+	}
+}
+
+func BenchmarkParserState(b *testing.B) {
+	input := []byte(strings.Repeat("\x1b[1;38;2;255;136;0mhello\x1b[0m ", 10))
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		p := ansi.Parser{}
+		for _, c := range input {
+			p.WriteByte(c)
+		}
+	}
+}
+
+// joinText returns tokens with adjacent Text tokens joined into one.
+func joinText(tokens []ansi.Token) []ansi.Token {
+	var joined []ansi.Token
+
+	for _, tok := range tokens {
+		if n := len(joined); n > 0 && tok.Kind == ansi.Text && joined[n-1].Kind == ansi.Text {
+			joined[n-1].Raw += tok.Raw
+			continue
+		}
+
+		joined = append(joined, tok)
+	}
+
+	return joined
+}
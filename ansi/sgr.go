@@ -0,0 +1,265 @@
+package ansi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AttrKind is the kind of an [Attr].
+type AttrKind int
+
+const (
+	Reset                  AttrKind = iota // Reset all attributes to their defaults
+	Bold                                   // Bold or increased intensity
+	Dim                                    // Dim or decreased intensity
+	Italic                                 // Italic
+	Underline                              // Underline
+	Blink                                  // Slow or rapid blink
+	Reverse                                // Swap the foreground and background colours
+	Hidden                                 // Hidden (concealed) text
+	Strikethrough                          // Strikethrough (crossed out)
+	NormalIntensity                        // Neither bold nor dim
+	NotItalic                              // Not italic
+	NotUnderline                           // Not underlined
+	NotBlink                               // Not blinking
+	NotReverse                             // Not reversed
+	NotHidden                              // Not hidden
+	NotStrikethrough                       // Not strikethrough
+	Foreground                             // Set the foreground colour to Colour
+	Background                             // Set the background colour to Colour
+	UnderlineColour                        // Set the underline colour to Colour
+	DefaultForeground                      // Reset the foreground colour to the default
+	DefaultBackground                      // Reset the background colour to the default
+	DefaultUnderlineColour                 // Reset the underline colour to the default
+	Unknown                                // An attribute not otherwise recognised, its SGR code is in Code
+)
+
+// String implements [fmt.Stringer] for an [AttrKind].
+func (k AttrKind) String() string {
+	switch k {
+	case Reset:
+		return "Reset"
+	case Bold:
+		return "Bold"
+	case Dim:
+		return "Dim"
+	case Italic:
+		return "Italic"
+	case Underline:
+		return "Underline"
+	case Blink:
+		return "Blink"
+	case Reverse:
+		return "Reverse"
+	case Hidden:
+		return "Hidden"
+	case Strikethrough:
+		return "Strikethrough"
+	case NormalIntensity:
+		return "NormalIntensity"
+	case NotItalic:
+		return "NotItalic"
+	case NotUnderline:
+		return "NotUnderline"
+	case NotBlink:
+		return "NotBlink"
+	case NotReverse:
+		return "NotReverse"
+	case NotHidden:
+		return "NotHidden"
+	case NotStrikethrough:
+		return "NotStrikethrough"
+	case Foreground:
+		return "Foreground"
+	case Background:
+		return "Background"
+	case UnderlineColour:
+		return "UnderlineColour"
+	case DefaultForeground:
+		return "DefaultForeground"
+	case DefaultBackground:
+		return "DefaultBackground"
+	case DefaultUnderlineColour:
+		return "DefaultUnderlineColour"
+	case Unknown:
+		return "Unknown"
+	default:
+		return fmt.Sprintf("AttrKind(%d)", int(k))
+	}
+}
+
+// Attr is a single attribute set by an [SGR] sequence e.g. bold, or a red foreground.
+type Attr struct {
+	Colour Colour   // The colour, for Foreground, Background and UnderlineColour attributes
+	Code   int      // The SGR code of an Unknown attribute
+	Kind   AttrKind // What kind of attribute it is
+}
+
+// ColourType is the type of a [Colour].
+type ColourType int
+
+const (
+	Basic      ColourType = iota // One of the 16 basic colours, Index 0-7 are the normal colours and 8-15 their bright variants
+	Indexed                      // A colour from the 256 colour xterm palette, given by Index
+	TrueColour                   // A 24 bit colour, given by R, G and B
+)
+
+// Colour is a colour set by an [SGR] sequence.
+type Colour struct {
+	Type  ColourType // Which fields describe the colour
+	Index uint8      // Index of a Basic or Indexed colour
+	R     uint8      // Red component of a TrueColour colour
+	G     uint8      // Green component of a TrueColour colour
+	B     uint8      // Blue component of a TrueColour colour
+}
+
+// attrs is the attribute for each SGR code that doesn't take arguments and isn't a basic colour.
+var attrs = map[int]AttrKind{
+	0:  Reset,
+	1:  Bold,
+	2:  Dim,
+	3:  Italic,
+	4:  Underline,
+	5:  Blink,
+	6:  Blink,
+	7:  Reverse,
+	8:  Hidden,
+	9:  Strikethrough,
+	22: NormalIntensity,
+	23: NotItalic,
+	24: NotUnderline,
+	25: NotBlink,
+	27: NotReverse,
+	28: NotHidden,
+	29: NotStrikethrough,
+	39: DefaultForeground,
+	49: DefaultBackground,
+	59: DefaultUnderlineColour,
+}
+
+// decodeSGR decodes the parameters of an SGR sequence (between the "ESC [" and
+// the 'm') into attributes.
+//
+// Extended colours may be given in either the common form with ';' separators, as
+// "38;5;208" or "38;2;255;136;0", or the ITU T.416 form with ':' separators, as "38:5:208"
+// or "38:2::255:136:0" (with an optional colour space identifier).
+func decodeSGR(params string) []Attr {
+	if params == "" {
+		return []Attr{{Kind: Reset}}
+	}
+
+	groups := strings.Split(params, ";")
+	result := make([]Attr, 0, len(groups))
+
+	for i := 0; i < len(groups); i++ {
+		parts := strings.Split(groups[i], ":")
+		code := number(parts[0])
+
+		switch {
+		case code >= 30 && code <= 37:
+			result = append(result, Attr{Kind: Foreground, Colour: Colour{Type: Basic, Index: uint8(code - 30)}})
+		case code >= 40 && code <= 47:
+			result = append(result, Attr{Kind: Background, Colour: Colour{Type: Basic, Index: uint8(code - 40)}})
+		case code >= 90 && code <= 97:
+			result = append(result, Attr{Kind: Foreground, Colour: Colour{Type: Basic, Index: uint8(code - 90 + 8)}})
+		case code >= 100 && code <= 107:
+			result = append(result, Attr{Kind: Background, Colour: Colour{Type: Basic, Index: uint8(code - 100 + 8)}})
+		case code == 38 || code == 48 || code == 58:
+			kind := map[int]AttrKind{38: Foreground, 48: Background, 58: UnderlineColour}[code]
+
+			var (
+				colour Colour
+				ok     bool
+			)
+
+			if len(parts) > 1 {
+				colour, ok = extendedColour(parts[1:], true)
+			} else {
+				var used int
+				colour, used, ok = semicolonColour(groups[i+1:])
+				i += used
+			}
+
+			if !ok {
+				result = append(result, Attr{Kind: Unknown, Code: code})
+				continue
+			}
+
+			result = append(result, Attr{Kind: kind, Colour: colour})
+		default:
+			if kind, ok := attrs[code]; ok {
+				result = append(result, Attr{Kind: kind})
+			} else {
+				result = append(result, Attr{Kind: Unknown, Code: code})
+			}
+		}
+	}
+
+	return result
+}
+
+// semicolonColour decodes an extended colour given with ';' separators from the groups
+// after the 38, 48 or 58, returning how many groups it used.
+func semicolonColour(groups []string) (colour Colour, used int, ok bool) {
+	if len(groups) == 0 {
+		return Colour{}, 0, false
+	}
+
+	switch groups[0] {
+	case "5":
+		if len(groups) < 2 { //nolint: mnd // Mode and index
+			return Colour{}, len(groups), false
+		}
+
+		colour, ok = extendedColour(groups[:2], false)
+
+		return colour, 2, ok //nolint: mnd // Mode and index
+	case "2":
+		if len(groups) < 4 { //nolint: mnd // Mode and 3 components
+			return Colour{}, len(groups), false
+		}
+
+		colour, ok = extendedColour(groups[:4], false)
+
+		return colour, 4, ok //nolint: mnd // Mode and 3 components
+	default:
+		return Colour{}, 1, false
+	}
+}
+
+// extendedColour decodes an extended colour from its mode ("5" or "2") and arguments. If
+// colourSpace is true, a truecolour may have a colour space identifier before its components.
+func extendedColour(args []string, colourSpace bool) (Colour, bool) {
+	switch {
+	case len(args) == 2 && args[0] == "5": //nolint: mnd // Mode and index
+		index, ok := component(args[1])
+		return Colour{Type: Indexed, Index: index}, ok
+	case args[0] == "2" && (len(args) == 4 || (colourSpace && len(args) == 5)): //nolint: mnd // Mode, optional colour space and 3 components
+		rgb := args[len(args)-3:]
+
+		r, okR := component(rgb[0])
+		g, okG := component(rgb[1])
+		b, okB := component(rgb[2])
+
+		return Colour{Type: TrueColour, R: r, G: g, B: b}, okR && okG && okB
+	default:
+		return Colour{}, false
+	}
+}
+
+// component parses a colour component or index, which must fit in a byte.
+func component(s string) (uint8, bool) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	return uint8(n), err == nil
+}
+
+// number parses an SGR code, an empty code is 0.
+func number(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
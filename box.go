@@ -0,0 +1,179 @@
+package hue
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Border is the set of characters used to draw the edges of a [Box].
+type Border struct {
+	TopLeft     string // Top left corner e.g. "┌"
+	Top         string // Top edge, repeated to fill the width e.g. "─"
+	TopRight    string // Top right corner e.g. "┐"
+	Left        string // Left edge e.g. "│"
+	Right       string // Right edge e.g. "│"
+	BottomLeft  string // Bottom left corner e.g. "└"
+	Bottom      string // Bottom edge, repeated to fill the width e.g. "─"
+	BottomRight string // Bottom right corner e.g. "┘"
+}
+
+var (
+	// SingleBorder draws a box with single lines, it is the default.
+	SingleBorder = Border{
+		TopLeft: "┌", Top: "─", TopRight: "┐",
+		Left: "│", Right: "│",
+		BottomLeft: "└", Bottom: "─", BottomRight: "┘",
+	}
+
+	// DoubleBorder draws a box with double lines.
+	DoubleBorder = Border{
+		TopLeft: "╔", Top: "═", TopRight: "╗",
+		Left: "║", Right: "║",
+		BottomLeft: "╚", Bottom: "═", BottomRight: "╝",
+	}
+
+	// RoundedBorder draws a box with single lines and rounded corners.
+	RoundedBorder = Border{
+		TopLeft: "╭", Top: "─", TopRight: "╮",
+		Left: "│", Right: "│",
+		BottomLeft: "╰", Bottom: "─", BottomRight: "╯",
+	}
+
+	// HeavyBorder draws a box with thick lines.
+	HeavyBorder = Border{
+		TopLeft: "┏", Top: "━", TopRight: "┓",
+		Left: "┃", Right: "┃",
+		BottomLeft: "┗", Bottom: "━", BottomRight: "┛",
+	}
+
+	// ASCIIBorder draws a box with plain ASCII characters, for terminals or fonts
+	// that don't support box drawing characters.
+	ASCIIBorder = Border{
+		TopLeft: "+", Top: "-", TopRight: "+",
+		Left: "|", Right: "|",
+		BottomLeft: "+", Bottom: "-", BottomRight: "+",
+	}
+)
+
+// Box frames content in a border, useful for panels, banners and summary messages.
+//
+// Content may already be styled, the width of each line is measured without counting
+// ANSI escape sequences so the right hand edge always lines up, and styles spanning
+// multiple lines are closed and re-opened around the border so they don't bleed into it.
+//
+//	box := hue.Box{Title: "Summary", Border: hue.RoundedBorder, Style: hue.Green, PaddingX: 1}
+//	box.Println("Build succeeded\n" + hue.Bold.Text("3") + " packages, 0 failures")
+//
+// The zero value is a valid Box with a [SingleBorder], no title and no padding.
+//
+// Like [go.followtheprocess.codes/hue/tabwriter], a Box assumes every rune has a width of 1,
+// content should not contain tabs.
+type Box struct {
+	Title      string    // Optional title, shown in the top border
+	Border     Border    // Characters to draw the border with, defaults to SingleBorder
+	Renderer   *Renderer // Renders the border and title styles, nil uses the package wide settings
+	Style      Style     // Style of the border
+	TitleStyle Style     // Style of the title, defaults to Style
+	PaddingX   int       // Number of spaces between the left and right edges and the content, < 0 is treated as 0
+	PaddingY   int       // Number of blank lines between the top and bottom edges and the content, < 0 is treated as 0
+	Width      int       // Minimum width of the content area (excluding padding)
+}
+
+// Text returns content framed in the box, without a trailing newline.
+func (b Box) Text(content string) string {
+	if b.Border == (Border{}) {
+		b.Border = SingleBorder
+	}
+
+	// Negative padding is meaningless, treat it as none
+	b.PaddingX = max(b.PaddingX, 0)
+	b.PaddingY = max(b.PaddingY, 0)
+
+	r := DefaultRenderer()
+	if b.Renderer != nil {
+		r = *b.Renderer
+	}
+
+	titleStyle := b.TitleStyle
+	if titleStyle == 0 {
+		titleStyle = b.Style
+	}
+
+	lines := splitLines(content)
+
+	width := b.Width
+	for _, line := range lines {
+		width = max(width, visibleWidth(line))
+	}
+
+	// " title " plus at least one edge character either side
+	if b.Title != "" {
+		width = max(width, visibleWidth(b.Title)+4-2*b.PaddingX) //nolint: mnd // See above
+	}
+
+	inner := width + 2*b.PaddingX
+	pad := strings.Repeat(" ", b.PaddingX)
+
+	s := &strings.Builder{}
+
+	// Top edge, with the title if there is one
+	if b.Title == "" {
+		s.WriteString(r.Text(b.Style, b.Border.TopLeft+strings.Repeat(b.Border.Top, inner)+b.Border.TopRight))
+	} else {
+		s.WriteString(r.Text(b.Style, b.Border.TopLeft+b.Border.Top+" "))
+		s.WriteString(r.Text(titleStyle, b.Title))
+
+		rest := inner - visibleWidth(b.Title) - 3 //nolint: mnd // Top + 2 spaces
+		s.WriteString(r.Text(b.Style, " "+strings.Repeat(b.Border.Top, rest)+b.Border.TopRight))
+	}
+
+	s.WriteByte('\n')
+
+	writeLine := func(line string) {
+		s.WriteString(r.Text(b.Style, b.Border.Left))
+		s.WriteString(pad)
+		s.WriteString(line)
+		s.WriteString(strings.Repeat(" ", width-visibleWidth(line)))
+		s.WriteString(pad)
+		s.WriteString(r.Text(b.Style, b.Border.Right))
+		s.WriteByte('\n')
+	}
+
+	for range b.PaddingY {
+		writeLine("")
+	}
+
+	for _, line := range lines {
+		writeLine(line)
+	}
+
+	for range b.PaddingY {
+		writeLine("")
+	}
+
+	s.WriteString(r.Text(b.Style, b.Border.BottomLeft+strings.Repeat(b.Border.Bottom, inner)+b.Border.BottomRight))
+
+	return s.String()
+}
+
+// Sprint formats using the default formats for its operands and returns the result
+// framed in the box, without a trailing newline. Spaces are added between operands
+// when neither is a string.
+func (b Box) Sprint(a ...any) string {
+	return b.Text(fmt.Sprint(a...))
+}
+
+// Fprintln formats using the default formats for its operands and writes the result
+// framed in the box to w, followed by a newline. Spaces are added between operands
+// when neither is a string. It returns the number of bytes written and any write
+// error encountered.
+func (b Box) Fprintln(w io.Writer, a ...any) (n int, err error) {
+	return fmt.Fprintln(w, b.Text(fmt.Sprint(a...)))
+}
+
+// Println is like [Box.Fprintln] but writes to [os.Stdout].
+func (b Box) Println(a ...any) (n int, err error) {
+	return b.Fprintln(os.Stdout, a...)
+}
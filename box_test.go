@@ -0,0 +1,149 @@
+package hue_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestBox(t *testing.T) {
+	tests := []struct {
+		name    string  // Name of the test case
+		content string  // Content to frame
+		want    string  // Expected result
+		box     hue.Box // Box under test
+		enabled bool    // Whether hue is enabled
+	}{
+		{
+			name:    "zero value",
+			content: "hello",
+			want: "┌─────┐\n" +
+				"│hello│\n" +
+				"└─────┘",
+		},
+		{
+			name:    "multi line",
+			content: "hello\nthere",
+			box:     hue.Box{Border: hue.RoundedBorder, PaddingX: 1},
+			want: "╭───────╮\n" +
+				"│ hello │\n" +
+				"│ there │\n" +
+				"╰───────╯",
+		},
+		{
+			name:    "ragged lines",
+			content: "a\nlonger line\nab",
+			box:     hue.Box{Border: hue.DoubleBorder},
+			want: "╔═══════════╗\n" +
+				"║a          ║\n" +
+				"║longer line║\n" +
+				"║ab         ║\n" +
+				"╚═══════════╝",
+		},
+		{
+			name:    "padding",
+			content: "hi",
+			box:     hue.Box{Border: hue.HeavyBorder, PaddingX: 2, PaddingY: 1},
+			want: "┏━━━━━━┓\n" +
+				"┃      ┃\n" +
+				"┃  hi  ┃\n" +
+				"┃      ┃\n" +
+				"┗━━━━━━┛",
+		},
+		{
+			name:    "title",
+			content: "Build succeeded",
+			box:     hue.Box{Title: "Summary", PaddingX: 1},
+			want: "┌─ Summary ───────┐\n" +
+				"│ Build succeeded │\n" +
+				"└─────────────────┘",
+		},
+		{
+			name:    "title wider than content",
+			content: "ok",
+			box:     hue.Box{Title: "A long title", Border: hue.ASCIIBorder},
+			want: "+- A long title -+\n" +
+				"|ok              |\n" +
+				"+----------------+",
+		},
+		{
+			name:    "negative padding",
+			content: "x",
+			box:     hue.Box{Title: "T", PaddingX: -1, PaddingY: -3, Border: hue.ASCIIBorder},
+			want: "+- T -+\n" +
+				"|x    |\n" +
+				"+-----+",
+		},
+		{
+			name:    "min width",
+			content: "ok",
+			box:     hue.Box{Width: 6, Border: hue.ASCIIBorder},
+			want: "+------+\n" +
+				"|ok    |\n" +
+				"+------+",
+		},
+		{
+			name:    "styled content is measured without escapes",
+			content: "\x1b[32mok\x1b[0m\nfail",
+			box:     hue.Box{Border: hue.ASCIIBorder},
+			enabled: true,
+			want: "+----+\n" +
+				"|\x1b[32mok\x1b[0m  |\n" +
+				"|fail|\n" +
+				"+----+",
+		},
+		{
+			name:    "style spanning lines does not bleed into border",
+			content: "\x1b[31mone\ntwo\x1b[0m",
+			box:     hue.Box{Border: hue.ASCIIBorder},
+			enabled: true,
+			want: "+---+\n" +
+				"|\x1b[31mone\x1b[0m|\n" +
+				"|\x1b[31mtwo\x1b[0m|\n" +
+				"+---+",
+		},
+		{
+			name:    "styled border and title",
+			content: "x",
+			box:     hue.Box{Title: "T", Border: hue.ASCIIBorder, Style: hue.Blue, TitleStyle: hue.Bold},
+			enabled: true,
+			want: "\x1b[34m+- \x1b[0m\x1b[1mT\x1b[0m\x1b[34m -+\x1b[0m\n" +
+				"\x1b[34m|\x1b[0mx    \x1b[34m|\x1b[0m\n" +
+				"\x1b[34m+-----+\x1b[0m",
+		},
+		{
+			name:    "styled border disabled",
+			content: "x",
+			box:     hue.Box{Border: hue.ASCIIBorder, Style: hue.Blue},
+			enabled: false,
+			want: "+-+\n" +
+				"|x|\n" +
+				"+-+",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(tt.enabled)
+
+			got := tt.box.Text(tt.content)
+			if got != tt.want {
+				t.Errorf("\nGot:\n%s\nWanted:\n%s\n\nGot:\t%s\nWanted:\t%s\n", got, tt.want, strconv.Quote(got), strconv.Quote(tt.want))
+			}
+		})
+	}
+}
+
+func TestBoxFprintln(t *testing.T) {
+	hue.Enabled(false)
+
+	buf := &bytes.Buffer{}
+	hue.Box{Border: hue.ASCIIBorder}.Fprintln(buf, "answer:", 42)
+
+	want := "+---------+\n|answer:42|\n+---------+\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nGot:\n%s\nWanted:\n%s\n", got, want)
+	}
+}
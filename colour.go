@@ -0,0 +1,188 @@
+package hue
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// level is the ColourLevel of the terminal, stored as a uint32 so it may be read
+// and set atomically.
+//
+// It defaults to automatic detection, but can be explicitly set by the user via [SetColourLevel].
+var level atomic.Uint32
+
+// ColourLevel describes the range of colours a terminal is able to show.
+//
+// The basic [Style] colours are supported everywhere, but [RGB] colours are downgraded to the
+// nearest colour the terminal supports.
+type ColourLevel uint32
+
+const (
+	Level16         ColourLevel = iota // The 16 basic ANSI colours e.g. Red, BrightRed
+	Level256                           // The 256 colour xterm palette
+	LevelTrueColour                    // 24 bit "truecolor"
+)
+
+// String implements [fmt.Stringer] for a [ColourLevel].
+func (l ColourLevel) String() string {
+	switch l {
+	case Level16:
+		return "16"
+	case Level256:
+		return "256"
+	case LevelTrueColour:
+		return "truecolour"
+	default:
+		return fmt.Sprintf("ColourLevel(%d)", uint32(l))
+	}
+}
+
+// SetColourLevel sets the range of colours the terminal is assumed to support.
+//
+// Hue defaults to automatic detection based on $COLORTERM and $TERM, this function may be
+// called to bypass detection and explicitly set the level.
+//
+// SetColourLevel may be called safely from concurrently executing goroutines.
+func SetColourLevel(l ColourLevel) {
+	level.Store(uint32(l))
+}
+
+// RGB is a 24 bit colour.
+//
+// When written to a terminal that doesn't support truecolour, an RGB colour is downgraded
+// to the nearest colour that it does, see [ColourLevel].
+type RGB struct {
+	R uint8 // Red
+	G uint8 // Green
+	B uint8 // Blue
+}
+
+// ParseHex parses a hex colour string, like those used in CSS, into an [RGB] colour.
+//
+// The leading '#' is optional and both the 6 digit "#ff8800" and 3 digit "#f80" forms are accepted.
+func ParseHex(hex string) (RGB, error) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) == 3 { //nolint: mnd // Short form, each digit is doubled
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+
+	if len(s) != 6 { //nolint: mnd // rrggbb
+		return RGB{}, fmt.Errorf("invalid hex colour %q: must be 3 or 6 hex digits", hex)
+	}
+
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex colour %q: %w", hex, err)
+	}
+
+	return RGB{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n)}, nil //nolint: gosec,mnd // Can't overflow, shifts are per byte
+}
+
+// String implements [fmt.Stringer] for an [RGB] colour, returning its hex form e.g. "#ff8800".
+func (c RGB) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Text returns text styled with c as its foreground colour. If colour is disabled, text is
+// returned unchanged.
+func (c RGB) Text(text string) string {
+	return DefaultRenderer().RGB(c, text)
+}
+
+// Lighten returns c lightened by amount, from 0 (unchanged) to 1 (white).
+//
+// Colours are blended towards white in the perceptually uniform OKLab colour space, so
+// equal amounts give visually equal steps.
+func (c RGB) Lighten(amount float64) RGB {
+	return c.Mix(RGB{R: 255, G: 255, B: 255}, amount)
+}
+
+// Darken returns c darkened by amount, from 0 (unchanged) to 1 (black).
+//
+// Like [RGB.Lighten], colours are blended towards black in the OKLab colour space.
+func (c RGB) Darken(amount float64) RGB {
+	return c.Mix(RGB{}, amount)
+}
+
+// Mix returns the colour a fraction t of the way from c to other, so a t of 0
+// returns c, 1 returns other and 0.5 an even mix of the two.
+//
+// Colours are mixed in the OKLab colour space, see [OKLab].
+func (c RGB) Mix(other RGB, t float64) RGB {
+	return mix(c, other, clamp(t), OKLab)
+}
+
+// clamp limits v to the range 0 to 1.
+func clamp(v float64) float64 {
+	return max(0, min(1, v))
+}
+
+// appendCode appends the SGR code (without the leading escape or trailing 'm') setting c as the
+// foreground (or background) colour to dst, downgraded to the given colour level and
+// transformed by the colour blindness simulation, if any.
+func (c RGB) appendCode(dst []byte, background bool, lvl ColourLevel, sim ColourBlindness) []byte {
+	if sim != NoSimulation {
+		c = c.Simulate(sim)
+	}
+
+	switch lvl {
+	case LevelTrueColour:
+		if background {
+			dst = append(dst, "48;2;"...)
+		} else {
+			dst = append(dst, "38;2;"...)
+		}
+
+		dst = strconv.AppendUint(dst, uint64(c.R), 10)
+		dst = append(dst, ';')
+		dst = strconv.AppendUint(dst, uint64(c.G), 10)
+		dst = append(dst, ';')
+
+		return strconv.AppendUint(dst, uint64(c.B), 10)
+	case Level256:
+		if background {
+			dst = append(dst, "48;5;"...)
+		} else {
+			dst = append(dst, "38;5;"...)
+		}
+
+		return strconv.AppendUint(dst, uint64(Nearest256(c)), 10)
+	default:
+		style := Nearest16(c)
+		if background {
+			style = style.toBackground()
+		}
+
+		dst, _ = style.appendCode(dst)
+
+		return dst
+	}
+}
+
+// toBackground returns the background equivalent of a single foreground colour style.
+func (s Style) toBackground() Style {
+	if (s >= Black && s <= White) || (s >= BrightBlack && s <= BrightWhite) {
+		// Each background colour is declared the same number of places after its foreground
+		return s * (BlackBackground / Black)
+	}
+
+	return s
+}
+
+// autoDetectLevel determines the range of colours the terminal supports
+// from its environment.
+func autoDetectLevel() ColourLevel {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return LevelTrueColour
+	}
+
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Level256
+	}
+
+	return Level16
+}
@@ -0,0 +1,147 @@
+package hue_test
+
+import (
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		name    string  // Name of the test case
+		hex     string  // Input hex string
+		errMsg  string  // Expected error message, if any
+		want    hue.RGB // Expected colour
+		wantErr bool    // Whether we expect an error
+	}{
+		{name: "full", hex: "#ff8800", want: hue.RGB{R: 255, G: 136, B: 0}},
+		{name: "no hash", hex: "1a2B3c", want: hue.RGB{R: 0x1a, G: 0x2b, B: 0x3c}},
+		{name: "short", hex: "#f80", want: hue.RGB{R: 255, G: 136, B: 0}},
+		{name: "black", hex: "#000000", want: hue.RGB{}},
+		{
+			name:    "wrong length",
+			hex:     "#ff88",
+			wantErr: true,
+			errMsg:  `invalid hex colour "#ff88": must be 3 or 6 hex digits`,
+		},
+		{
+			name:    "not hex",
+			hex:     "#gg8800",
+			wantErr: true,
+			errMsg:  `invalid hex colour "#gg8800": strconv.ParseUint: parsing "gg8800": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hue.ParseHex(tt.hex)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHex(%q) returned error %v, wanted error: %v", tt.hex, err, tt.wantErr)
+			}
+
+			if err != nil {
+				if err.Error() != tt.errMsg {
+					t.Errorf("\nGot error:\t%s\nWanted:\t\t%s\n", err, tt.errMsg)
+				}
+
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, tt.want)
+			}
+
+			if tt.hex[0] == '#' && len(tt.hex) == 7 && got.String() != tt.hex {
+				t.Errorf("String() round trip: got %s, wanted %s", got.String(), tt.hex)
+			}
+		})
+	}
+}
+
+func TestRGBText(t *testing.T) {
+	red := hue.RGB{R: 215, G: 0, B: 0}
+
+	tests := []struct {
+		name    string          // Name of the test case
+		want    string          // Expected result
+		level   hue.ColourLevel // Colour level of the terminal
+		enabled bool            // Whether hue is enabled
+	}{
+		{name: "truecolour", level: hue.LevelTrueColour, enabled: true, want: "\x1b[38;2;215;0;0mhello\x1b[0m"},
+		{name: "256", level: hue.Level256, enabled: true, want: "\x1b[38;5;160mhello\x1b[0m"},
+		{name: "16", level: hue.Level16, enabled: true, want: "\x1b[31mhello\x1b[0m"},
+		{name: "disabled", level: hue.LevelTrueColour, enabled: false, want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(tt.enabled)
+			hue.SetColourLevel(tt.level)
+
+			got := strconv.Quote(red.Text("hello"))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestColourLevelString(t *testing.T) {
+	tests := []struct {
+		want  string
+		level hue.ColourLevel
+	}{
+		{level: hue.Level16, want: "16"},
+		{level: hue.Level256, want: "256"},
+		{level: hue.LevelTrueColour, want: "truecolour"},
+		{level: hue.ColourLevel(42), want: "ColourLevel(42)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.want {
+			t.Errorf("ColourLevel(%d).String() = %q, wanted %q", uint32(tt.level), got, tt.want)
+		}
+	}
+}
+
+func TestLightenDarkenMix(t *testing.T) {
+	var (
+		black = hue.RGB{}
+		white = hue.RGB{R: 255, G: 255, B: 255}
+		red   = hue.RGB{R: 255}
+		blue  = hue.RGB{B: 255}
+	)
+
+	tests := []struct {
+		name string  // Name of the test case
+		got  hue.RGB // Result of the operation
+		want hue.RGB // Expected colour
+	}{
+		{name: "lighten none", got: red.Lighten(0), want: red},
+		{name: "lighten fully", got: red.Lighten(1), want: white},
+		{name: "lighten clamped", got: red.Lighten(3), want: white},
+		{name: "darken none", got: red.Darken(0), want: red},
+		{name: "darken fully", got: red.Darken(1), want: black},
+		{name: "darken grey", got: white.Darken(0.5), want: hue.RGB{R: 99, G: 99, B: 99}},
+		{name: "mix start", got: red.Mix(blue, 0), want: red},
+		{name: "mix end", got: red.Mix(blue, 1), want: blue},
+		{name: "mix middle", got: black.Mix(white, 0.5), want: hue.RGB{R: 99, G: 99, B: 99}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", tt.got, tt.want)
+			}
+		})
+	}
+
+	// Lightening should always make a colour lighter
+	brown := hue.RGB{R: 100, G: 50, B: 20}
+	if brown.Lighten(0.3).Luminance() <= brown.Luminance() {
+		t.Error("Lighten did not increase luminance")
+	}
+}
@@ -0,0 +1,125 @@
+package hue
+
+// WCAG 2 minimum contrast ratios between text and its background.
+//
+// See https://www.w3.org/TR/WCAG21/#contrast-minimum.
+const (
+	ContrastAALarge = 3.0 // Minimum contrast for large or bold text
+	ContrastAA      = 4.5 // Minimum contrast for normal text
+	ContrastAAA     = 7.0 // Enhanced contrast for normal text
+)
+
+// Luminance returns the WCAG relative luminance of c, from 0 for black to 1 for white.
+func (c RGB) Luminance() float64 {
+	return 0.2126*toLinear(c.R) + 0.7152*toLinear(c.G) + 0.0722*toLinear(c.B) //nolint: mnd // WCAG coefficients
+}
+
+// Contrast returns the WCAG contrast ratio between two colours, from 1 (no contrast) to
+// 21 (black on white). The order of the colours doesn't matter.
+//
+// Compare the result against [ContrastAA] or [ContrastAAA] to check text in one colour is
+// readable on a background of the other.
+func Contrast(a, b RGB) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+
+	return (la + 0.05) / (lb + 0.05) //nolint: mnd // WCAG flare term
+}
+
+// ReadableOn returns the colour from candidates with the highest contrast against the
+// background colour, defaulting to a choice of black or white if no candidates are given.
+func ReadableOn(background RGB, candidates ...RGB) RGB {
+	if len(candidates) == 0 {
+		candidates = []RGB{{}, {R: 255, G: 255, B: 255}}
+	}
+
+	best, bestContrast := candidates[0], 0.0
+	for _, candidate := range candidates {
+		if c := Contrast(candidate, background); c > bestContrast {
+			best, bestContrast = candidate, c
+		}
+	}
+
+	return best
+}
+
+// Foreground returns the colour of the foreground of s, or false if s does not set a
+// foreground colour.
+//
+// The exact colours of the basic styles are up to the terminal, the xterm defaults are used.
+func (s Style) Foreground() (RGB, bool) {
+	for i, style := range basic {
+		if s&style != 0 {
+			return basicRGB[i], true
+		}
+	}
+
+	return RGB{}, false
+}
+
+// Background returns the colour of the background of s, or false if s does not set a
+// background colour.
+//
+// The exact colours of the basic styles are up to the terminal, the xterm defaults are used.
+func (s Style) Background() (RGB, bool) {
+	for i, style := range basic {
+		if s&style.toBackground() != 0 {
+			return basicRGB[i], true
+		}
+	}
+
+	return RGB{}, false
+}
+
+// Contrast returns the WCAG contrast ratio between the foreground and background colours
+// of s, or false if s doesn't set both.
+//
+// This can be used to check a combination such as hue.White | hue.YellowBackground is
+// readable, see [ContrastAA].
+func (s Style) Contrast() (float64, bool) {
+	fg, ok := s.Foreground()
+	if !ok {
+		return 0, false
+	}
+
+	bg, ok := s.Background()
+	if !ok {
+		return 0, false
+	}
+
+	return Contrast(fg, bg), true
+}
+
+// Readable returns s with its foreground colour replaced, if necessary, by whichever of the
+// basic foreground colours has the highest contrast against its background, so that it
+// meets at least minimum contrast ratio (e.g. [ContrastAA]) if any colour can.
+//
+// If s has no background colour, or its foreground already has enough contrast, s is
+// returned unchanged. All other attributes of s (e.g. Bold) are preserved.
+func (s Style) Readable(minimum float64) Style {
+	bg, ok := s.Background()
+	if !ok {
+		return s
+	}
+
+	if fg, ok := s.Foreground(); ok && Contrast(fg, bg) >= minimum {
+		return s
+	}
+
+	best := ReadableOn(bg, basicRGB[:]...)
+
+	// Clear any existing foreground colour before setting the new one
+	for _, style := range basic {
+		s &^= style
+	}
+
+	for i, rgb := range basicRGB {
+		if rgb == best {
+			return s | basic[i]
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,125 @@
+package hue_test
+
+import (
+	"math"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestContrast(t *testing.T) {
+	tests := []struct {
+		name string  // Name of the test case
+		a, b hue.RGB // Colours to compare
+		want float64 // Expected contrast ratio
+	}{
+		{name: "black on white", a: hue.RGB{}, b: hue.RGB{R: 255, G: 255, B: 255}, want: 21},
+		{name: "white on black", a: hue.RGB{R: 255, G: 255, B: 255}, b: hue.RGB{}, want: 21},
+		{name: "same colour", a: hue.RGB{R: 12, G: 34, B: 56}, b: hue.RGB{R: 12, G: 34, B: 56}, want: 1},
+		{name: "grey on white", a: hue.RGB{R: 118, G: 118, B: 118}, b: hue.RGB{R: 255, G: 255, B: 255}, want: 4.54},
+		{name: "red on white", a: hue.RGB{R: 255}, b: hue.RGB{R: 255, G: 255, B: 255}, want: 4.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hue.Contrast(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Contrast(%v, %v) = %.3f, wanted %.2f", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadableOn(t *testing.T) {
+	var (
+		black  = hue.RGB{}
+		white  = hue.RGB{R: 255, G: 255, B: 255}
+		yellow = hue.RGB{R: 255, G: 220}
+		navy   = hue.RGB{B: 128}
+	)
+
+	if got := hue.ReadableOn(yellow); got != black {
+		t.Errorf("ReadableOn(yellow) = %v, wanted black", got)
+	}
+
+	if got := hue.ReadableOn(navy); got != white {
+		t.Errorf("ReadableOn(navy) = %v, wanted white", got)
+	}
+
+	if got := hue.ReadableOn(navy, navy, yellow, black); got != yellow {
+		t.Errorf("ReadableOn(navy, candidates...) = %v, wanted yellow", got)
+	}
+}
+
+func TestStyleColours(t *testing.T) {
+	style := hue.Bold | hue.BrightBlue | hue.RedBackground
+
+	fg, ok := style.Foreground()
+	if !ok || fg != (hue.RGB{R: 92, G: 92, B: 255}) {
+		t.Errorf("Foreground() = %v, %v, wanted bright blue", fg, ok)
+	}
+
+	bg, ok := style.Background()
+	if !ok || bg != (hue.RGB{R: 205}) {
+		t.Errorf("Background() = %v, %v, wanted red", bg, ok)
+	}
+
+	if _, ok := hue.Bold.Foreground(); ok {
+		t.Error("Bold reported a foreground colour")
+	}
+
+	if _, ok := hue.Green.Background(); ok {
+		t.Error("Green reported a background colour")
+	}
+
+	if _, ok := hue.Green.Contrast(); ok {
+		t.Error("Green reported a contrast without a background")
+	}
+}
+
+func TestStyleReadable(t *testing.T) {
+	tests := []struct {
+		name  string    // Name of the test case
+		style hue.Style // Style under test
+		want  hue.Style // Expected readable style
+	}{
+		{
+			name:  "white on yellow",
+			style: hue.White | hue.YellowBackground,
+			want:  hue.Black | hue.YellowBackground,
+		},
+		{
+			name:  "modifiers kept",
+			style: hue.Bold | hue.Underline | hue.BrightYellow | hue.BrightWhiteBackground,
+			want:  hue.Bold | hue.Underline | hue.Black | hue.BrightWhiteBackground,
+		},
+		{
+			name:  "no foreground",
+			style: hue.Italic | hue.BlueBackground,
+			want:  hue.Italic | hue.BrightWhite | hue.BlueBackground,
+		},
+		{
+			name:  "already readable",
+			style: hue.Black | hue.WhiteBackground,
+			want:  hue.Black | hue.WhiteBackground,
+		},
+		{
+			name:  "no background",
+			style: hue.Yellow,
+			want:  hue.Yellow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.style.Readable(hue.ContrastAA)
+			if got != tt.want {
+				t.Errorf("Readable() = %d, wanted %d", got, tt.want)
+			}
+
+			if contrast, ok := got.Contrast(); ok && contrast < hue.ContrastAA {
+				t.Errorf("Readable() style has contrast %.2f, below AA", contrast)
+			}
+		})
+	}
+}
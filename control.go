@@ -0,0 +1,207 @@
+package hue
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Raw control sequences, used directly by the parts of hue that have already checked
+// whether escapes are enabled.
+const (
+	eraseLine  = escape + "K"     // eraseLine clears from the cursor to the end of the line.
+	eraseDown  = escape + "J"     // eraseDown clears from the cursor to the end of the screen.
+	hideCursor = escape + "?25l"  // hideCursor stops the terminal drawing the cursor.
+	showCursor = escape + "?25h"  // showCursor restores the cursor hidden by hideCursor.
+	linkOpen   = "\x1b]8;"        // linkOpen starts an OSC 8 hyperlink, followed by its parameters and URI.
+	linkClose  = "\x1b]8;;\x1b\\" // linkClose closes the hyperlink in effect.
+)
+
+// Control is a terminal control sequence e.g. to move the cursor or clear the screen.
+//
+// Like a [Style], a Control respects whether hue is enabled (see [Enabled]): when disabled it
+// renders as nothing at all, so callers don't need to check before writing one. Controls
+// implement [fmt.Stringer] so may be printed directly:
+//
+//	fmt.Print(hue.HideCursor)
+//	defer fmt.Print(hue.ShowCursor)
+//
+// Or appended to a buffer without allocating:
+//
+//	buf = hue.CursorUp(3).Append(buf)
+//	buf = hue.EraseLine.Append(buf)
+//
+// The zero value is an empty sequence.
+type Control struct {
+	prefix  string // Introducer and any fixed parameters e.g. "\x1b[?25"
+	text    string // Text following the numeric parameters, e.g. the window title
+	final   string // Terminator e.g. "A" or "\a"
+	params  [2]int // Numeric parameters, if any
+	nparams int    // Number of params in use
+}
+
+var (
+	SaveCursor     = Control{prefix: "\x1b7"}           // Save the cursor position
+	RestoreCursor  = Control{prefix: "\x1b8"}           // Restore the cursor position saved by SaveCursor
+	HideCursor     = Control{prefix: hideCursor}        // Stop drawing the cursor
+	ShowCursor     = Control{prefix: showCursor}        // Start drawing the cursor again after HideCursor
+	EraseLine      = Control{prefix: escape + "2K"}     // Clear the whole of the current line
+	EraseLineRight = Control{prefix: eraseLine}         // Clear from the cursor to the end of the line
+	EraseLineLeft  = Control{prefix: escape + "1K"}     // Clear from the start of the line to the cursor
+	EraseScreen    = Control{prefix: escape + "2J"}     // Clear the whole screen
+	EraseDown      = Control{prefix: eraseDown}         // Clear from the cursor to the end of the screen
+	EraseUp        = Control{prefix: escape + "1J"}     // Clear from the start of the screen to the cursor
+	CursorHome     = Control{prefix: escape + "H"}      // Move the cursor to the top left of the screen
+	AltScreen      = Control{prefix: escape + "?1049h"} // Switch to the alternate screen buffer, as full screen programs do
+	ExitAltScreen  = Control{prefix: escape + "?1049l"} // Switch back to the main screen buffer after AltScreen
+)
+
+// CursorUp moves the cursor up n lines, staying in the same column.
+func CursorUp(n int) Control {
+	return cursor(n, "A")
+}
+
+// CursorDown moves the cursor down n lines, staying in the same column.
+func CursorDown(n int) Control {
+	return cursor(n, "B")
+}
+
+// CursorForward moves the cursor right n columns.
+func CursorForward(n int) Control {
+	return cursor(n, "C")
+}
+
+// CursorBack moves the cursor left n columns.
+func CursorBack(n int) Control {
+	return cursor(n, "D")
+}
+
+// CursorColumn moves the cursor to column col of the current line. Columns are numbered
+// from 1, as they are by the terminal.
+func CursorColumn(col int) Control {
+	return cursor(col, "G")
+}
+
+// CursorTo moves the cursor to the given row and column. Rows and columns are numbered
+// from 1, as they are by the terminal, so CursorTo(1, 1) is the top left of the screen.
+func CursorTo(row, col int) Control {
+	if row < 1 || col < 1 {
+		return Control{}
+	}
+
+	return Control{prefix: escape, params: [2]int{row, col}, nparams: 2, final: "H"}
+}
+
+// SetTitle sets the title of the terminal window. Any control characters in title,
+// which would otherwise terminate the sequence early, are removed.
+func SetTitle(title string) Control {
+	return Control{prefix: "\x1b]0;", text: stripControls(title), final: "\a"}
+}
+
+// stripControls returns s without any C0 or C1 control characters (or DEL), any of which
+// could end an escape sequence s is written into early, and inject another in its place.
+func stripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+// cursor returns a cursor movement by n. Terminals treat a movement of 0 as 1, so
+// n < 1 returns an empty Control rather than moving unexpectedly.
+func cursor(n int, final string) Control {
+	if n < 1 {
+		return Control{}
+	}
+
+	return Control{prefix: escape, params: [2]int{n}, nparams: 1, final: final}
+}
+
+// String implements [fmt.Stringer] for a [Control], returning the escape sequence
+// or "" if hue is disabled.
+func (c Control) String() string {
+	return DefaultRenderer().Control(c)
+}
+
+// Append appends the escape sequence for c to dst, returning the extended buffer.
+//
+// If hue is disabled, dst is returned unchanged.
+func (c Control) Append(dst []byte) []byte {
+	return DefaultRenderer().AppendControl(dst, c)
+}
+
+// Control returns the escape sequence for c, or "" if r doesn't colourise output,
+// see [Control.String].
+func (r Renderer) Control(c Control) string {
+	if !r.Colour {
+		return ""
+	}
+
+	if c.nparams == 0 && c.text == "" && c.final == "" {
+		// Fixed sequence, no need to allocate
+		return c.prefix
+	}
+
+	return string(c.append(nil))
+}
+
+// AppendControl appends the escape sequence for c to dst, returning the extended buffer.
+// If r doesn't colourise output, dst is returned unchanged.
+func (r Renderer) AppendControl(dst []byte, c Control) []byte {
+	if !r.Colour {
+		return dst
+	}
+
+	return c.append(dst)
+}
+
+// append appends the escape sequence for c to dst, regardless of any settings.
+func (c Control) append(dst []byte) []byte {
+	dst = append(dst, c.prefix...)
+	for i := range c.nparams {
+		if i > 0 {
+			dst = append(dst, ';')
+		}
+
+		dst = strconv.AppendInt(dst, int64(c.params[i]), 10)
+	}
+
+	dst = append(dst, c.text...)
+	dst = append(dst, c.final...)
+
+	return dst
+}
+
+// Hyperlink returns text as a clickable link to url, using the OSC 8 escape sequence supported
+// by most modern terminals. Terminals that don't support it show just the text.
+//
+// If hue is disabled, the url is shown in brackets after the text instead so it isn't lost
+// e.g. "the docs (https://example.com)". If text is empty or the same as url, just url is shown.
+//
+// Any control characters in url, which would otherwise end the sequence early, are removed.
+// Text is written as is so it may be styled, so must not contain control characters from an
+// untrusted source.
+func Hyperlink(url, text string) string {
+	return DefaultRenderer().Hyperlink(url, text)
+}
+
+// Hyperlink is like the package level [Hyperlink] but renders with the settings of r.
+func (r Renderer) Hyperlink(url, text string) string {
+	url = stripControls(url)
+
+	if text == "" {
+		text = url
+	}
+
+	if !r.Colour {
+		if text == url {
+			return url
+		}
+
+		return text + " (" + url + ")"
+	}
+
+	return linkOpen + ";" + url + "\x1b\\" + text + linkClose
+}
@@ -0,0 +1,121 @@
+package hue_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestControl(t *testing.T) {
+	tests := []struct {
+		name    string      // Name of the test case
+		want    string      // Expected escape sequence
+		control hue.Control // Control under test
+	}{
+		{name: "zero", control: hue.Control{}, want: ""},
+		{name: "save cursor", control: hue.SaveCursor, want: "\x1b7"},
+		{name: "restore cursor", control: hue.RestoreCursor, want: "\x1b8"},
+		{name: "hide cursor", control: hue.HideCursor, want: "\x1b[?25l"},
+		{name: "show cursor", control: hue.ShowCursor, want: "\x1b[?25h"},
+		{name: "erase line", control: hue.EraseLine, want: "\x1b[2K"},
+		{name: "erase line right", control: hue.EraseLineRight, want: "\x1b[K"},
+		{name: "erase line left", control: hue.EraseLineLeft, want: "\x1b[1K"},
+		{name: "erase screen", control: hue.EraseScreen, want: "\x1b[2J"},
+		{name: "erase down", control: hue.EraseDown, want: "\x1b[J"},
+		{name: "erase up", control: hue.EraseUp, want: "\x1b[1J"},
+		{name: "cursor home", control: hue.CursorHome, want: "\x1b[H"},
+		{name: "alt screen", control: hue.AltScreen, want: "\x1b[?1049h"},
+		{name: "exit alt screen", control: hue.ExitAltScreen, want: "\x1b[?1049l"},
+		{name: "up", control: hue.CursorUp(3), want: "\x1b[3A"},
+		{name: "down", control: hue.CursorDown(12), want: "\x1b[12B"},
+		{name: "forward", control: hue.CursorForward(1), want: "\x1b[1C"},
+		{name: "back", control: hue.CursorBack(2), want: "\x1b[2D"},
+		{name: "up zero", control: hue.CursorUp(0), want: ""},
+		{name: "back negative", control: hue.CursorBack(-1), want: ""},
+		{name: "column", control: hue.CursorColumn(5), want: "\x1b[5G"},
+		{name: "to", control: hue.CursorTo(10, 20), want: "\x1b[10;20H"},
+		{name: "to invalid", control: hue.CursorTo(0, 20), want: ""},
+		{name: "title", control: hue.SetTitle("hue"), want: "\x1b]0;hue\a"},
+		{name: "title control chars", control: hue.SetTitle("a\ab\x1b[31m\u009c"), want: "\x1b]0;ab[31m\a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+
+			got := strconv.Quote(tt.control.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nString:\t%v\nWanted:\t%v\n", got, want)
+			}
+
+			appended := strconv.Quote(string(tt.control.Append([]byte("x"))))
+			if wantAppended := strconv.Quote("x" + tt.want); appended != wantAppended {
+				t.Errorf("\nAppend:\t%v\nWanted:\t%v\n", appended, wantAppended)
+			}
+
+			hue.Enabled(false)
+
+			if got := tt.control.String(); got != "" {
+				t.Errorf("String() with hue disabled = %q, wanted \"\"", got)
+			}
+
+			if got := string(tt.control.Append([]byte("x"))); got != "x" {
+				t.Errorf("Append() with hue disabled = %q, wanted \"x\"", got)
+			}
+		})
+	}
+}
+
+func TestControlPrint(t *testing.T) {
+	hue.Enabled(true)
+
+	got := strconv.Quote(fmt.Sprintf("%s%shello", hue.CursorUp(2), hue.EraseLine))
+	want := strconv.Quote("\x1b[2A\x1b[2Khello")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func BenchmarkControlAppend(b *testing.B) {
+	hue.Enabled(true)
+
+	buf := make([]byte, 0, 64)
+
+	for b.Loop() {
+		buf = hue.CursorTo(24, 80).Append(buf[:0])
+	}
+}
+
+func TestHyperlink(t *testing.T) {
+	tests := []struct {
+		name    string // Name of the test case
+		url     string // URL to link to
+		text    string // Text of the link
+		want    string // Expected result
+		enabled bool   // Whether hue is enabled
+	}{
+		{name: "enabled", url: "https://go.dev", text: "Go", enabled: true, want: "\x1b]8;;https://go.dev\x1b\\Go\x1b]8;;\x1b\\"},
+		{name: "no text", url: "https://go.dev", enabled: true, want: "\x1b]8;;https://go.dev\x1b\\https://go.dev\x1b]8;;\x1b\\"},
+		{name: "control chars", url: "https://go.dev\x1b\\\x1b[2J\a\u009c", text: "Go", enabled: true, want: "\x1b]8;;https://go.dev\\[2J\x1b\\Go\x1b]8;;\x1b\\"},
+		{name: "disabled", url: "https://go.dev", text: "Go", enabled: false, want: "Go (https://go.dev)"},
+		{name: "disabled same", url: "https://go.dev", text: "https://go.dev", enabled: false, want: "https://go.dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(tt.enabled)
+
+			got := strconv.Quote(hue.Hyperlink(tt.url, tt.text))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+// Package diff computes line diffs between two texts and renders them with [hue.Style]s,
+// either in unified format or side by side, for showing e.g. "expected vs got" output
+// in tests or changes to a config file.
+//
+// Differences are computed with the Myers algorithm, which finds the smallest possible
+// set of changes. Changed lines are then compared word by word so that the parts of the
+// line that actually changed can be highlighted:
+//
+//	r := diff.Renderer{
+//		Insert:     hue.Green,
+//		Delete:     hue.Red,
+//		InsertWord: hue.Green | hue.Bold | hue.Underline,
+//		DeleteWord: hue.Red | hue.Bold | hue.Underline,
+//		Header:     hue.Cyan,
+//	}
+//	r.Unified(os.Stdout, want, got)
+package diff // import "go.followtheprocess.codes/hue/diff"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of an [Edit].
+type Op int
+
+const (
+	Equal  Op = iota // The line is in both texts
+	Delete           // The line is only in the old text
+	Insert           // The line is only in the new text
+)
+
+// String implements [fmt.Stringer] for an [Op].
+func (o Op) String() string {
+	switch o {
+	case Equal:
+		return "Equal"
+	case Delete:
+		return "Delete"
+	case Insert:
+		return "Insert"
+	default:
+		return fmt.Sprintf("Op(%d)", int(o))
+	}
+}
+
+// Edit is a single line of a diff.
+type Edit struct {
+	Text string // The line, without its trailing newline
+	Old  int    // Line number in the old text, from 1, or 0 for an Insert
+	New  int    // Line number in the new text, from 1, or 0 for a Delete
+	Op   Op     // Whether the line is unchanged, deleted or inserted
+}
+
+// Hunk is a group of nearby changes along with their surrounding context.
+type Hunk struct {
+	Edits    []Edit // Lines in the hunk, in order
+	OldStart int    // Line number of the hunk's first line in the old text
+	OldLines int    // Number of lines of the old text in the hunk
+	NewStart int    // Line number of the hunk's first line in the new text
+	NewLines int    // Number of lines of the new text in the hunk
+}
+
+// Header returns the unified diff header for the hunk e.g. "@@ -1,3 +1,4 @@".
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", span(h.OldStart, h.OldLines), span(h.NewStart, h.NewLines))
+}
+
+// span formats a line range in a hunk header, omitting a count of 1 as diff does.
+func span(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// Lines returns the line by line diff between old and new, containing every line of both.
+//
+// A trailing newline at the end of either text is ignored.
+func Lines(old, new string) []Edit { //nolint: predeclared // old and new are the clearest names
+	a, b := split(old), split(new)
+
+	ops := myers(a, b)
+	edits := make([]Edit, 0, len(ops))
+
+	for _, o := range ops {
+		switch o.op {
+		case Equal:
+			edits = append(edits, Edit{Op: Equal, Text: a[o.a], Old: o.a + 1, New: o.b + 1})
+		case Delete:
+			edits = append(edits, Edit{Op: Delete, Text: a[o.a], Old: o.a + 1})
+		case Insert:
+			edits = append(edits, Edit{Op: Insert, Text: b[o.b], New: o.b + 1})
+		}
+	}
+
+	return edits
+}
+
+// Hunks groups the changes in edits into hunks, each with up to context unchanged lines
+// either side of it. Changes separated by no more than 2*context unchanged lines share a hunk.
+//
+// If there are no changes, Hunks returns nil.
+func Hunks(edits []Edit, context int) []Hunk {
+	context = max(context, 0)
+
+	var hunks []Hunk
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+
+		// Find the end of this hunk, the first run of more than 2*context equal lines
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != Equal {
+				end = j + 1
+				continue
+			}
+
+			if j-end >= 2*context {
+				break
+			}
+		}
+
+		end = min(end+context, len(edits))
+		hunks = append(hunks, newHunk(edits, start, end))
+		i = end
+	}
+
+	return hunks
+}
+
+// newHunk returns the hunk of edits[start:end].
+func newHunk(edits []Edit, start, end int) Hunk {
+	h := Hunk{Edits: edits[start:end]}
+
+	// Number of lines of each text before the hunk
+	oldBefore, newBefore := 0, 0
+	for _, e := range edits[:start] {
+		if e.Op != Insert {
+			oldBefore++
+		}
+
+		if e.Op != Delete {
+			newBefore++
+		}
+	}
+
+	for _, e := range h.Edits {
+		if e.Op != Insert {
+			h.OldLines++
+		}
+
+		if e.Op != Delete {
+			h.NewLines++
+		}
+	}
+
+	// Like diff, an empty range starts at the line before it
+	h.OldStart, h.NewStart = oldBefore, newBefore
+	if h.OldLines != 0 {
+		h.OldStart++
+	}
+
+	if h.NewLines != 0 {
+		h.NewStart++
+	}
+
+	return h
+}
+
+// split splits text into lines, ignoring a trailing newline.
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// op is a single step in the edit script between two sequences a and b.
+type op struct {
+	op Op  // Kind of step
+	a  int // Index in a, if Equal or Delete
+	b  int // Index in b, if Equal or Insert
+}
+
+// myers returns the shortest edit script turning a into b, using the linear space variation of
+// the algorithm from Eugene W. Myers (1986), "An O(ND) Difference Algorithm and Its Variations".
+//
+// Rather than remembering every round of the search to walk back along the path found, which
+// takes O((N+M)D) memory, it finds the middle snake of the path and recurses either side of it.
+func myers[T comparable](a, b []T) []op {
+	size := 2*((len(a)+len(b)+1)/2+1) + 1 //nolint: mnd // Diagonals -(D+1) to D+1, D being at most half of N+M
+	d := differ[T]{
+		a:       a,
+		b:       b,
+		forward: make([]int, size),
+		reverse: make([]int, size),
+		ops:     make([]op, 0, max(len(a), len(b))),
+	}
+
+	d.compare(0, len(a), 0, len(b))
+
+	return d.ops
+}
+
+// differ holds the state of a diff between a and b.
+type differ[T comparable] struct {
+	a       []T   // The old sequence
+	b       []T   // The new sequence
+	forward []int // Furthest x reached on each diagonal searching forward from the start, reused by every search
+	reverse []int // Furthest x reached on each diagonal searching back from the end, counting from the end
+	ops     []op  // The edit script so far
+}
+
+// compare appends the edit script turning a[aLo:aHi] into b[bLo:bHi] to d.ops.
+func (d *differ[T]) compare(aLo, aHi, bLo, bHi int) {
+	// Equal elements at either end are always part of the shortest edit script
+	start, bStart := aLo, bLo
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		aLo++
+		bLo++
+	}
+
+	d.equal(start, aLo, bStart)
+
+	end := aHi
+	for aHi > aLo && bHi > bLo && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.ops = append(d.ops, op{op: Insert, b: y})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.ops = append(d.ops, op{op: Delete, a: x})
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		d.equal(x, u, y)
+		d.compare(u, aHi, v, bHi)
+	}
+
+	d.equal(aHi, end, bHi)
+}
+
+// equal appends Equal steps pairing a[aLo:aHi] with b from bLo.
+func (d *differ[T]) equal(aLo, aHi, bLo int) {
+	for x := aLo; x < aHi; x++ {
+		d.ops = append(d.ops, op{op: Equal, a: x, b: bLo + x - aLo})
+	}
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of a shortest edit
+// script turning a[aLo:aHi] into b[bLo:bHi], both of which must be non-empty.
+//
+// It searches from both ends at once until the paths overlap, the snake where they do splits
+// the script into two halves each needing half as many edits.
+func (d *differ[T]) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m // Diagonal of the end point, reverse diagonal c is forward diagonal delta-c
+	odd := delta%2 != 0
+	offset := len(d.forward) / 2 //nolint: mnd // So that diagonals may be negative
+
+	fwd, rev := d.forward, d.reverse
+	fwd[offset+1] = 0
+	rev[offset+1] = 0
+
+	for depth := 0; depth <= (n+m+1)/2; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			var x int
+			if k == -depth || (k != depth && fwd[offset+k-1] < fwd[offset+k+1]) {
+				x = fwd[offset+k+1] // Move down, an insertion
+			} else {
+				x = fwd[offset+k-1] + 1 // Move right, a deletion
+			}
+
+			y := x - k
+			startX, startY := x, y
+
+			// Follow the snake of equal elements
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+
+			fwd[offset+k] = x
+
+			if c := delta - k; odd && c >= -(depth-1) && c <= depth-1 && x+rev[offset+c] >= n {
+				return aLo + startX, bLo + startY, aLo + x, bLo + y
+			}
+		}
+
+		for c := -depth; c <= depth; c += 2 {
+			var x int
+			if c == -depth || (c != depth && rev[offset+c-1] < rev[offset+c+1]) {
+				x = rev[offset+c+1]
+			} else {
+				x = rev[offset+c-1] + 1
+			}
+
+			y := x - c
+			startX, startY := x, y
+
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+
+			rev[offset+c] = x
+
+			if k := delta - c; !odd && k >= -depth && k <= depth && x+fwd[offset+k] >= n {
+				return aHi - x, bHi - y, aHi - startX, bHi - startY
+			}
+		}
+	}
+
+	panic("diff: no middle snake found") // Unreachable, the paths always meet by (N+M+1)/2
+}
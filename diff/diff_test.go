@@ -0,0 +1,335 @@
+package diff_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/diff"
+)
+
+func TestLines(t *testing.T) {
+	got := diff.Lines("a\nb\nc\n", "a\nB\nc\nd")
+	want := []diff.Edit{
+		{Op: diff.Equal, Text: "a", Old: 1, New: 1},
+		{Op: diff.Delete, Text: "b", Old: 2},
+		{Op: diff.Insert, Text: "B", New: 2},
+		{Op: diff.Equal, Text: "c", Old: 3, New: 3},
+		{Op: diff.Insert, Text: "d", New: 4},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, want)
+	}
+}
+
+func TestLinesReconstruct(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	text := func() string {
+		lines := make([]string, rng.IntN(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.IntN(4)))
+		}
+
+		return strings.Join(lines, "\n")
+	}
+
+	for range 200 {
+		old, new := text(), text()
+
+		var gotOld, gotNew []string
+
+		for _, e := range diff.Lines(old, new) {
+			if e.Op != diff.Insert {
+				gotOld = append(gotOld, e.Text)
+			}
+
+			if e.Op != diff.Delete {
+				gotNew = append(gotNew, e.Text)
+			}
+		}
+
+		if strings.Join(gotOld, "\n") != old || strings.Join(gotNew, "\n") != new {
+			t.Fatalf("edits of %q -> %q don't reconstruct both texts", old, new)
+		}
+	}
+}
+
+func TestLinesShortest(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	for range 200 {
+		a := make([]string, rng.IntN(40))
+		for i := range a {
+			a[i] = string(rune('a' + rng.IntN(3)))
+		}
+
+		b := make([]string, rng.IntN(40))
+		for i := range b {
+			b[i] = string(rune('a' + rng.IntN(3)))
+		}
+
+		// The shortest edit script keeps a longest common subsequence
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		equal := 0
+
+		for _, e := range diff.Lines(strings.Join(a, "\n"), strings.Join(b, "\n")) {
+			if e.Op == diff.Equal {
+				equal++
+			}
+		}
+
+		if equal != lcs[0][0] {
+			t.Fatalf("diff of %q -> %q kept %d lines, the longest common subsequence is %d", a, b, equal, lcs[0][0])
+		}
+	}
+}
+
+func TestLinesLarge(t *testing.T) {
+	old, new := disjoint(5000)
+
+	var deletes, inserts int
+
+	for _, e := range diff.Lines(old, new) {
+		switch e.Op {
+		case diff.Delete:
+			deletes++
+		case diff.Insert:
+			inserts++
+		default:
+			t.Fatalf("unexpected edit %+v between texts with no lines in common", e)
+		}
+	}
+
+	if deletes != 5000 || inserts != 5000 {
+		t.Errorf("got %d deletes and %d inserts, wanted 5000 of each", deletes, inserts)
+	}
+}
+
+func BenchmarkLines(b *testing.B) {
+	b.Run("disjoint", func(b *testing.B) {
+		old, new := disjoint(5000)
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			diff.Lines(old, new)
+		}
+	})
+
+	b.Run("similar", func(b *testing.B) {
+		lines := make([]string, 5000)
+		for i := range lines {
+			lines[i] = "line " + strconv.Itoa(i)
+		}
+
+		old := strings.Join(lines, "\n")
+
+		for i := 0; i < len(lines); i += 100 {
+			lines[i] = "changed"
+		}
+
+		new := strings.Join(lines, "\n")
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			diff.Lines(old, new)
+		}
+	})
+}
+
+// disjoint returns two texts of n lines with no lines in common.
+func disjoint(n int) (old, new string) { //nolint: predeclared // Clearest names
+	a := make([]string, n)
+	b := make([]string, n)
+
+	for i := range n {
+		a[i] = "old " + strconv.Itoa(i)
+		b[i] = "new " + strconv.Itoa(i)
+	}
+
+	return strings.Join(a, "\n"), strings.Join(b, "\n")
+}
+
+func TestHunkHeaders(t *testing.T) {
+	// Expected headers are from GNU diff -u
+	tests := []struct {
+		name    string   // Name of the test case
+		old     string   // Old text
+		new     string   // New text
+		want    []string // Expected hunk headers
+		context int      // Lines of context
+	}{
+		{
+			name:    "two hunks",
+			old:     "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+			new:     "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			context: 3,
+			want:    []string{"@@ -1,6 +1,6 @@", "@@ -11,3 +11,4 @@"},
+		},
+		{
+			name:    "merged",
+			old:     "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n",
+			new:     "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			context: 5,
+			want:    []string{"@@ -1,13 +1,14 @@"},
+		},
+		{name: "from empty", old: "", new: "x\n", context: 3, want: []string{"@@ -0,0 +1 @@"}},
+		{name: "to empty", old: "x\n", new: "", context: 3, want: []string{"@@ -1 +0,0 @@"}},
+		{name: "no context", old: "a\nb\nc\n", new: "a\nc\n", context: 0, want: []string{"@@ -2 +1,0 @@"}},
+		{name: "same", old: "a\nb\n", new: "a\nb\n", context: 3, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, hunk := range diff.Hunks(diff.Lines(tt.old, tt.new), tt.context) {
+				got = append(got, hunk.Header())
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string        // Name of the test case
+		old      string        // Old text
+		new      string        // New text
+		want     string        // Expected output
+		renderer diff.Renderer // Renderer under test
+		side     bool          // Render side by side rather than unified
+	}{
+		{
+			name:     "unified",
+			renderer: diff.Renderer{OldName: "want", NewName: "got", Context: 1},
+			old:      "a\nb\nc\nd\ne\n",
+			new:      "a\nb\nC\nd\ne\n",
+			want:     "--- want\n+++ got\n@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n",
+		},
+		{
+			name:     "same",
+			renderer: diff.Renderer{},
+			old:      "a\n",
+			new:      "a\n",
+			want:     "",
+		},
+		{
+			name:     "styled",
+			renderer: diff.Renderer{Context: -1, Delete: hue.Red, Insert: hue.Green, Header: hue.Cyan},
+			old:      "a\nb\n",
+			new:      "a\nc\n",
+			want:     "\x1b[36m@@ -2 +2 @@\x1b[0m\n\x1b[31m-b\x1b[0m\n\x1b[32m+c\x1b[0m\n",
+		},
+		{
+			name:     "styled plain renderer",
+			renderer: diff.Renderer{Renderer: &hue.Renderer{}, Context: -1, Delete: hue.Red, Insert: hue.Green, Header: hue.Cyan},
+			old:      "a\nb\n",
+			new:      "a\nc\n",
+			want:     "@@ -2 +2 @@\n-b\n+c\n",
+		},
+		{
+			name: "words",
+			renderer: diff.Renderer{
+				Context:    -1,
+				Delete:     hue.Red,
+				Insert:     hue.Green,
+				DeleteWord: hue.Red | hue.Bold,
+				InsertWord: hue.Green | hue.Bold,
+			},
+			old: "name: hue\n",
+			new: "name: diff\n",
+			want: "@@ -1 +1 @@\n" +
+				"\x1b[31m-name: \x1b[0m\x1b[1;31mhue\x1b[0m\n" +
+				"\x1b[32m+name: \x1b[0m\x1b[1;32mdiff\x1b[0m\n",
+		},
+		{
+			name:     "side by side",
+			renderer: diff.Renderer{Context: 1},
+			side:     true,
+			old:      "hello world\nsame\nremoved\n",
+			new:      "hello there world\nsame\n",
+			want: "@@ -1,3 +1,2 @@\n" +
+				"-hello world │ +hello there world\n" +
+				" same        │  same\n" +
+				"-removed     │\n",
+		},
+		{
+			name:     "side by side styled",
+			renderer: diff.Renderer{Context: -1, Delete: hue.Red, Insert: hue.Green},
+			side:     true,
+			old:      "a\nbb\n",
+			new:      "aaa\nbb\n",
+			want: "@@ -1 +1 @@\n" +
+				"\x1b[31m-a\x1b[0m │ \x1b[32m+aaa\x1b[0m\n",
+		},
+		{
+			name:     "side by side plain renderer",
+			renderer: diff.Renderer{Renderer: &hue.Renderer{}, Context: -1, Delete: hue.Red, Insert: hue.Green},
+			side:     true,
+			old:      "a\nbb\n",
+			new:      "aaa\nbb\n",
+			want:     "@@ -1 +1 @@\n-a │ +aaa\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+
+			buf := &bytes.Buffer{}
+
+			render := tt.renderer.Unified
+			if tt.side {
+				render = tt.renderer.SideBySide
+			}
+
+			if err := render(buf, tt.old, tt.new); err != nil {
+				t.Fatalf("render returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestOpString(t *testing.T) {
+	for op, want := range map[diff.Op]string{
+		diff.Equal:  "Equal",
+		diff.Delete: "Delete",
+		diff.Insert: "Insert",
+		diff.Op(7):  "Op(7)",
+	} {
+		if got := op.String(); got != want {
+			t.Errorf("Op(%d).String() = %q, wanted %q", int(op), got, want)
+		}
+	}
+}
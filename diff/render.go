@@ -0,0 +1,309 @@
+package diff
+
+import (
+	"io"
+	"strings"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tabwriter"
+)
+
+const (
+	defaultContext = 3 // Lines of context around each change, as diff -u
+	padding        = 1 // Minimum spaces between the columns of a side by side diff
+	tabWidth       = 4 // Spaces to replace tabs with in a side by side diff
+)
+
+// Renderer renders the difference between two texts. The zero value is ready to use and
+// renders an unstyled diff with 3 lines of context.
+type Renderer struct {
+	Renderer   *hue.Renderer // Renders the styles, nil uses the package wide settings
+	OldName    string        // Name of the old text for the "---" header line of a unified diff, omitted if empty
+	NewName    string        // Name of the new text for the "+++" header line of a unified diff, omitted if empty
+	Context    int           // Unchanged lines to show around each change, defaults to 3, < 0 for none
+	Equal      hue.Style     // Style for unchanged lines
+	Delete     hue.Style     // Style for deleted lines
+	Insert     hue.Style     // Style for inserted lines
+	DeleteWord hue.Style     // Style for the words that changed within a deleted line, 0 disables word highlighting
+	InsertWord hue.Style     // Style for the words that changed within an inserted line, 0 disables word highlighting
+	Header     hue.Style     // Style for file and hunk headers
+}
+
+// Unified writes the difference between old and new to w in unified diff format.
+//
+// Nothing is written if the texts are the same.
+func (r Renderer) Unified(w io.Writer, old, new string) error { //nolint: predeclared // Clearest names
+	hunks := Hunks(Lines(old, new), r.context())
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	r.Renderer = r.renderer()
+
+	var buf []byte
+
+	if r.OldName != "" || r.NewName != "" {
+		buf = r.Renderer.AppendString(buf, r.Header, "--- "+r.OldName)
+		buf = append(buf, '\n')
+		buf = r.Renderer.AppendString(buf, r.Header, "+++ "+r.NewName)
+		buf = append(buf, '\n')
+	}
+
+	for _, hunk := range hunks {
+		buf = r.Renderer.AppendString(buf, r.Header, hunk.Header())
+		buf = append(buf, '\n')
+
+		for _, line := range r.highlight(hunk.Edits) {
+			buf = append(buf, line...)
+			buf = append(buf, '\n')
+		}
+	}
+
+	_, err := w.Write(buf)
+
+	return err
+}
+
+// SideBySide writes the difference between old and new to w as two columns, the old text
+// on the left and the new on the right, aligned with [tabwriter].
+//
+// Deleted lines are paired with the inserted lines that replaced them. Tabs in either text
+// are replaced with spaces so as not to disturb the alignment. Nothing is written if the
+// texts are the same.
+func (r Renderer) SideBySide(w io.Writer, old, new string) error { //nolint: predeclared // Clearest names
+	old = strings.ReplaceAll(old, "\t", strings.Repeat(" ", tabWidth))
+	new = strings.ReplaceAll(new, "\t", strings.Repeat(" ", tabWidth))
+
+	hunks := Hunks(Lines(old, new), r.context())
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	r.Renderer = r.renderer()
+
+	tw := tabwriter.NewWriter(w, 0, tabWidth, padding, ' ', 0)
+
+	var buf []byte
+
+	for _, hunk := range hunks {
+		buf = r.Renderer.AppendString(buf, r.Header, hunk.Header())
+		buf = append(buf, '\n')
+
+		lines := r.highlight(hunk.Edits)
+
+		for i := 0; i < len(hunk.Edits); {
+			if hunk.Edits[i].Op == Equal {
+				buf = appendRow(buf, lines[i], lines[i])
+				i++
+
+				continue
+			}
+
+			// A run of deletions followed by the insertions that replaced them
+			deletes, inserts := changes(hunk.Edits[i:])
+			for j := range max(len(deletes), len(inserts)) {
+				var left, right string
+				if j < len(deletes) {
+					left = lines[i+deletes[j]]
+				}
+
+				if j < len(inserts) {
+					right = lines[i+inserts[j]]
+				}
+
+				buf = appendRow(buf, left, right)
+			}
+
+			i += len(deletes) + len(inserts)
+		}
+	}
+
+	if _, err := tw.Write(buf); err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+// appendRow appends a row of a side by side diff to dst.
+func appendRow(dst []byte, left, right string) []byte {
+	dst = append(dst, left...)
+	dst = append(dst, "\t│"...)
+
+	if right != "" {
+		dst = append(dst, ' ')
+		dst = append(dst, right...)
+	}
+
+	return append(dst, '\n')
+}
+
+// context returns the number of context lines to show.
+func (r Renderer) context() int {
+	switch {
+	case r.Context == 0:
+		return defaultContext
+	case r.Context < 0:
+		return 0
+	default:
+		return r.Context
+	}
+}
+
+// renderer returns the hue Renderer to render styles with.
+func (r Renderer) renderer() *hue.Renderer {
+	if r.Renderer != nil {
+		return r.Renderer
+	}
+
+	renderer := hue.DefaultRenderer()
+
+	return &renderer
+}
+
+// highlight renders each edit as a line prefixed with its marker (' ', '-' or '+'), highlighting
+// the words that changed between deleted lines and the inserted lines that replaced them.
+func (r Renderer) highlight(edits []Edit) []string {
+	lines := make([]string, len(edits))
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			lines[i] = r.Renderer.Text(r.Equal, " "+edits[i].Text)
+			i++
+
+			continue
+		}
+
+		deletes, inserts := changes(edits[i:])
+
+		for _, d := range deletes {
+			lines[i+d] = r.Renderer.Text(r.Delete, "-"+edits[i+d].Text)
+		}
+
+		for _, n := range inserts {
+			lines[i+n] = r.Renderer.Text(r.Insert, "+"+edits[i+n].Text)
+		}
+
+		if r.DeleteWord != 0 || r.InsertWord != 0 {
+			for j := range min(len(deletes), len(inserts)) {
+				d, n := i+deletes[j], i+inserts[j]
+				lines[d], lines[n] = r.words(edits[d].Text, edits[n].Text)
+			}
+		}
+
+		i += len(deletes) + len(inserts)
+	}
+
+	return lines
+}
+
+// words renders a deleted line and the inserted line that replaced it, highlighting the
+// words that differ between them.
+func (r Renderer) words(old, new string) (string, string) { //nolint: predeclared // Clearest names
+	a, b := tokenise(old), tokenise(new)
+
+	var oldLine, newLine []byte
+
+	// Accumulate runs of tokens with the same style, so each run is styled once
+	var oldRun, newRun strings.Builder
+
+	oldRun.WriteString("-")
+	newRun.WriteString("+")
+
+	oldChanged, newChanged := false, false
+
+	flushOld := func(changed bool) {
+		if changed != oldChanged && oldRun.Len() != 0 {
+			oldLine = r.Renderer.AppendString(oldLine, r.style(r.Delete, r.DeleteWord, oldChanged), oldRun.String())
+			oldRun.Reset()
+		}
+
+		oldChanged = changed
+	}
+
+	flushNew := func(changed bool) {
+		if changed != newChanged && newRun.Len() != 0 {
+			newLine = r.Renderer.AppendString(newLine, r.style(r.Insert, r.InsertWord, newChanged), newRun.String())
+			newRun.Reset()
+		}
+
+		newChanged = changed
+	}
+
+	for _, o := range myers(a, b) {
+		switch o.op {
+		case Equal:
+			flushOld(false)
+			oldRun.WriteString(a[o.a])
+			flushNew(false)
+			newRun.WriteString(b[o.b])
+		case Delete:
+			flushOld(true)
+			oldRun.WriteString(a[o.a])
+		case Insert:
+			flushNew(true)
+			newRun.WriteString(b[o.b])
+		}
+	}
+
+	oldLine = r.Renderer.AppendString(oldLine, r.style(r.Delete, r.DeleteWord, oldChanged), oldRun.String())
+	newLine = r.Renderer.AppendString(newLine, r.style(r.Insert, r.InsertWord, newChanged), newRun.String())
+
+	return string(oldLine), string(newLine)
+}
+
+// style returns the style for a run of tokens within a changed line.
+func (r Renderer) style(line, word hue.Style, changed bool) hue.Style {
+	if changed && word != 0 {
+		return word
+	}
+
+	return line
+}
+
+// changes returns the indices, relative to the start of edits, of the run of deletions at
+// the start of edits and of the run of insertions that immediately follow them.
+func changes(edits []Edit) (deletes, inserts []int) {
+	i := 0
+	for ; i < len(edits) && edits[i].Op == Delete; i++ {
+		deletes = append(deletes, i)
+	}
+
+	for ; i < len(edits) && edits[i].Op == Insert; i++ {
+		inserts = append(inserts, i)
+	}
+
+	return deletes, inserts
+}
+
+// tokenise splits a line into words, runs of spaces and individual punctuation characters,
+// the units in which changes within a line are highlighted.
+func tokenise(line string) []string {
+	var tokens []string
+
+	for len(line) > 0 {
+		n := 1
+
+		switch c := line[0]; {
+		case c == ' ' || c == '\t':
+			for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+				n++
+			}
+		case isWord(c):
+			for n < len(line) && isWord(line[n]) {
+				n++
+			}
+		}
+
+		tokens = append(tokens, line[:n])
+		line = line[n:]
+	}
+
+	return tokens
+}
+
+// isWord reports whether c is part of a word. Bytes of multi-byte runes are always
+// considered part of a word so runes are never split.
+func isWord(c byte) bool {
+	return c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,342 @@
+// Package errfmt formats errors and stack traces for display on a terminal.
+//
+// [Formatter.Error] unwraps an error, following both [fmt.Errorf]'s %w and [errors.Join],
+// into an indented tree of causes:
+//
+//	deploying service
+//	└── connecting to database
+//	    └── dial tcp 10.0.0.1:5432: connection refused
+//
+// And [Formatter.Stack] pretty prints a stack trace, from [Callers] or parsed from the output
+// of [runtime/debug.Stack] with [ParseStack], highlighting the frames from your own module
+// so they stand out from those in the standard library and dependencies.
+package errfmt // import "go.followtheprocess.codes/hue/errfmt"
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tree"
+)
+
+// Formatter formats errors and stack traces. The zero value formats them without any styles,
+// see [Default] for a styled Formatter.
+type Formatter struct {
+	Renderer  *hue.Renderer // Renders the styles, nil uses the package wide settings
+	Module    string        // Module path whose frames are highlighted, defaults to the main module
+	Message   hue.Style     // Style for the message of the outermost error, or a panic value
+	Cause     hue.Style     // Style for the messages of the errors it wraps
+	Connector hue.Style     // Style for the lines connecting the causes
+	Own       hue.Style     // Style for the function names of frames from Module
+	Other     hue.Style     // Style for frames from the standard library and dependencies
+	Location  hue.Style     // Style for the file and line of frames from Module
+}
+
+// Default is a [Formatter] with sensible styles.
+var Default = Formatter{
+	Message:   hue.Red | hue.Bold,
+	Cause:     hue.Red,
+	Connector: hue.BrightBlack,
+	Own:       hue.Bold,
+	Other:     hue.BrightBlack,
+	Location:  hue.Cyan,
+}
+
+// Error writes err to w as a tree of causes.
+//
+// Each error's message is shown without the messages of the errors it wraps, which are shown
+// beneath it instead. Errors created by [errors.Join] have no message of their own, so their
+// errors are shown directly beneath the error that wraps them. Control characters in messages, such
+// as newlines and tabs, are replaced with spaces.
+//
+// Nothing is written if err is nil.
+func (f Formatter) Error(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	root := f.node(err, true)
+	if root.Label == "" && len(root.Children) != 0 {
+		// A joined error at the root, show how many there are in place of a message
+		root.Label = strconv.Itoa(len(root.Children)) + " errors occurred"
+		root.Style = f.Message
+	}
+
+	renderer := f.renderer()
+
+	return tree.Renderer{Renderer: &renderer, Connector: f.Connector}.Render(w, root)
+}
+
+// node returns the tree of causes for err, root reports whether err is the outermost error.
+func (f Formatter) node(err error, root bool) *tree.Node {
+	style := f.Cause
+	if root {
+		style = f.Message
+	}
+
+	var causes []error
+
+	switch e := err.(type) { //nolint: errorlint // Deliberately only looking at this error, not its chain
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			causes = []error{cause}
+		}
+	}
+
+	msg := message(err, causes)
+	if msg == "" && len(causes) == 1 && causes[0] != nil {
+		// Wrapped with nothing added e.g. fmt.Errorf("%w", err)
+		return f.node(causes[0], root)
+	}
+
+	n := &tree.Node{Label: msg}
+	if n.Label != "" {
+		n.Style = style
+	}
+
+	for _, cause := range causes {
+		if cause == nil {
+			continue
+		}
+
+		child := f.node(cause, false)
+		if child.Label == "" {
+			// Joined errors have no message of their own, hoist their errors up a level
+			n.Children = append(n.Children, child.Children...)
+			continue
+		}
+
+		n.Children = append(n.Children, child)
+	}
+
+	return n
+}
+
+// message returns the message for err alone, without that of its causes, on a single line.
+func message(err error, causes []error) string {
+	msg := err.Error()
+
+	switch len(causes) {
+	case 0:
+	case 1:
+		// fmt.Errorf("context: %w", cause) is by far the most common, strip the cause
+		// back off to get the context
+		msg = strings.TrimSuffix(msg, causes[0].Error())
+		msg = strings.TrimRight(msg, ": ")
+	default:
+		msgs := make([]string, 0, len(causes))
+		for _, cause := range causes {
+			if cause != nil {
+				msgs = append(msgs, cause.Error())
+			}
+		}
+
+		if msg == strings.Join(msgs, "\n") {
+			return "" // errors.Join
+		}
+	}
+
+	return sanitise(msg)
+}
+
+// sanitise replaces the control characters in msg with spaces, as tabs and newlines would
+// otherwise break the lines and columns of the tree. Escape characters are kept, as styled
+// messages are laid out correctly. Any trailing spaces left are trimmed.
+func sanitise(msg string) string {
+	msg = strings.Map(func(r rune) rune {
+		if r != '\x1b' && unicode.IsControl(r) {
+			return ' '
+		}
+
+		return r
+	}, msg)
+
+	return strings.TrimRight(msg, " ")
+}
+
+// Frame is a single frame of a stack trace.
+type Frame struct {
+	Function string // Fully qualified function name e.g. "net/http.(*Server).Serve"
+	File     string // Path to the source file
+	Line     int    // Line number in File
+}
+
+// Callers returns the stack of the calling goroutine, skip is the number of frames to skip
+// with 0 being the caller of Callers.
+func Callers(skip int) []Frame {
+	const depth = 64
+
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs) //nolint: mnd // Skip runtime.Callers and Callers itself
+
+	var stack []Frame
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// ParseStack parses a stack trace in the format printed by the Go runtime and returned by
+// [runtime/debug.Stack]. Goroutine headers are skipped, so the frames of every goroutine in
+// the trace are returned in order.
+func ParseStack(stack []byte) []Frame {
+	var frames []Frame
+
+	lines := bytes.Split(stack, []byte("\n"))
+	for i := 0; i < len(lines); i++ {
+		line := string(lines[i])
+		if line == "" || line[0] == '\t' || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+
+		function := strings.TrimPrefix(line, "created by ")
+		if idx := strings.LastIndex(function, " in goroutine "); idx != -1 {
+			function = function[:idx]
+		}
+
+		// Strip the arguments e.g. main.run(0x1, {0x2, 0x3})
+		if strings.HasSuffix(function, ")") {
+			if idx := strings.LastIndex(function, "("); idx > 0 {
+				function = function[:idx]
+			}
+		}
+
+		frame := Frame{Function: function}
+
+		// Location is on the next line e.g. "\t/path/to/file.go:42 +0x1d"
+		if i+1 < len(lines) && bytes.HasPrefix(lines[i+1], []byte("\t")) {
+			i++
+			location := strings.TrimPrefix(string(lines[i]), "\t")
+			if idx := strings.LastIndex(location, " +0x"); idx != -1 {
+				location = location[:idx]
+			}
+
+			if idx := strings.LastIndex(location, ":"); idx != -1 {
+				if n, err := strconv.Atoi(location[idx+1:]); err == nil {
+					frame.File, frame.Line = location[:idx], n
+				}
+			}
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+// Stack writes frames to w, one per line followed by its location, highlighting those
+// from the Formatter's Module.
+func (f Formatter) Stack(w io.Writer, frames []Frame) error {
+	module := f.Module
+	if module == "" {
+		module = mainModule()
+	}
+
+	renderer := f.renderer()
+
+	var buf []byte
+
+	for _, frame := range frames {
+		function, location := f.Other, f.Other
+		if own(frame.Function, module) {
+			function, location = f.Own, f.Location
+		}
+
+		buf = renderer.AppendString(buf, function, frame.Function)
+		buf = append(buf, '\n')
+
+		if frame.File != "" {
+			buf = append(buf, "    "...)
+			buf = renderer.AppendString(buf, location, frame.File+":"+strconv.Itoa(frame.Line))
+			buf = append(buf, '\n')
+		}
+	}
+
+	_, err := w.Write(buf)
+
+	return err
+}
+
+// Panic writes a recovered panic value and the stack trace of the panicking goroutine,
+// as returned by [runtime/debug.Stack], to w:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			errfmt.Default.Panic(os.Stderr, r, debug.Stack())
+//			os.Exit(2)
+//		}
+//	}()
+//
+// If the panic value is an error, its tree of causes is written as for [Formatter.Error].
+func (f Formatter) Panic(w io.Writer, value any, stack []byte) error {
+	renderer := f.renderer()
+
+	if _, err := io.WriteString(w, renderer.Text(f.Message, "panic: ")); err != nil {
+		return err
+	}
+
+	if err, ok := value.(error); ok {
+		if err := f.Error(w, err); err != nil {
+			return err
+		}
+	} else if _, err := renderer.Fprintln(w, f.Message, value); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+
+	return f.Stack(w, ParseStack(stack))
+}
+
+// renderer returns the hue Renderer to render styles with.
+func (f Formatter) renderer() hue.Renderer {
+	if f.Renderer != nil {
+		return *f.Renderer
+	}
+
+	return hue.DefaultRenderer()
+}
+
+// own reports whether function belongs to module, or to the main package.
+func own(function, module string) bool {
+	if strings.HasPrefix(function, "main.") {
+		return true
+	}
+
+	if module == "" {
+		return false
+	}
+
+	// Must be the module itself or a package within it, not just share a prefix
+	rest, ok := strings.CutPrefix(function, module)
+
+	return ok && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "/"))
+}
+
+// mainModule returns the path of the main module, if it can be determined.
+func mainModule() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	return info.Main.Path
+}
@@ -0,0 +1,222 @@
+package errfmt_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/errfmt"
+)
+
+// stack is the output of debug.Stack from a goroutine started by a sync.WaitGroup.
+const stack = `goroutine 7 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+main.main.func1()
+	/tmp/stack.go:11 +0x13
+example.com/app/internal/db.(*Conn).Query(0xc000012345, {0x4b2a1c, 0x5})
+	/home/me/app/internal/db/conn.go:42 +0x1d
+sync.(*WaitGroup).Go.func1()
+	/usr/local/go/src/sync/waitgroup.go:258 +0x4a
+created by sync.(*WaitGroup).Go in goroutine 1
+	/usr/local/go/src/sync/waitgroup.go:238 +0x7d
+`
+
+func TestError(t *testing.T) {
+	refused := errors.New("dial tcp 10.0.0.1:5432: connection refused")
+	timeout := errors.New("i/o timeout")
+
+	tests := []struct {
+		err       error            // Error to format
+		name      string           // Name of the test case
+		want      string           // Expected output
+		formatter errfmt.Formatter // Formatter under test
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "single", err: refused, want: "dial tcp 10.0.0.1:5432: connection refused\n"},
+		{
+			name: "chain",
+			err:  fmt.Errorf("deploying service: %w", fmt.Errorf("connecting to database: %w", refused)),
+			want: "deploying service\n" +
+				"└── connecting to database\n" +
+				"    └── dial tcp 10.0.0.1:5432: connection refused\n",
+		},
+		{
+			name: "join",
+			err:  fmt.Errorf("health check: %w", errors.Join(refused, fmt.Errorf("cache: %w", timeout))),
+			want: "health check\n" +
+				"├── dial tcp 10.0.0.1:5432: connection refused\n" +
+				"└── cache\n" +
+				"    └── i/o timeout\n",
+		},
+		{
+			name: "root join",
+			err:  errors.Join(refused, timeout),
+			want: "2 errors occurred\n" +
+				"├── dial tcp 10.0.0.1:5432: connection refused\n" +
+				"└── i/o timeout\n",
+		},
+		{
+			name: "control characters",
+			err:  fmt.Errorf("reading\tconfig: %w", errors.Join(errors.New("line 1:\tbad\vkey\f"), errors.New("invalid \xff byte"))),
+			want: "reading config\n" +
+				"├── line 1: bad key\n" +
+				"└── invalid \ufffd byte\n",
+		},
+		{
+			name: "empty message",
+			err:  errors.New(""),
+			want: "\n",
+		},
+		{
+			name: "bare wrap",
+			err:  fmt.Errorf("%w", fmt.Errorf("ctx: %w", timeout)),
+			want: "ctx\n└── i/o timeout\n",
+		},
+		{
+			name: "multiple %w",
+			err:  fmt.Errorf("both %w and %w", refused, timeout),
+			want: "both dial tcp 10.0.0.1:5432: connection refused and i/o timeout\n" +
+				"├── dial tcp 10.0.0.1:5432: connection refused\n" +
+				"└── i/o timeout\n",
+		},
+		{
+			name:      "styled",
+			formatter: errfmt.Formatter{Message: hue.Red | hue.Bold, Cause: hue.Red, Connector: hue.BrightBlack},
+			err:       fmt.Errorf("saving: %w", timeout),
+			want:      "\x1b[1;31msaving\x1b[0m\n\x1b[90m└── \x1b[0m\x1b[31mi/o timeout\x1b[0m\n",
+		},
+		{
+			name: "styled plain renderer",
+			formatter: errfmt.Formatter{
+				Renderer:  &hue.Renderer{},
+				Message:   hue.Red | hue.Bold,
+				Cause:     hue.Red,
+				Connector: hue.BrightBlack,
+			},
+			err:  fmt.Errorf("saving: %w", timeout),
+			want: "saving\n└── i/o timeout\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+
+			buf := &bytes.Buffer{}
+			if err := tt.formatter.Error(buf, tt.err); err != nil {
+				t.Fatalf("Error returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestParseStack(t *testing.T) {
+	got := errfmt.ParseStack([]byte(stack))
+	want := []errfmt.Frame{
+		{Function: "runtime/debug.Stack", File: "/usr/local/go/src/runtime/debug/stack.go", Line: 26},
+		{Function: "main.main.func1", File: "/tmp/stack.go", Line: 11},
+		{Function: "example.com/app/internal/db.(*Conn).Query", File: "/home/me/app/internal/db/conn.go", Line: 42},
+		{Function: "sync.(*WaitGroup).Go.func1", File: "/usr/local/go/src/sync/waitgroup.go", Line: 258},
+		{Function: "sync.(*WaitGroup).Go", File: "/usr/local/go/src/sync/waitgroup.go", Line: 238},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, want)
+	}
+
+	// A real one should parse, and start with the function that called debug.Stack
+	frames := errfmt.ParseStack(debug.Stack())
+	if len(frames) < 2 || frames[1].Function != "go.followtheprocess.codes/hue/errfmt_test.TestParseStack" {
+		t.Errorf("unexpected frames from debug.Stack: %+v", frames)
+	}
+}
+
+func TestCallers(t *testing.T) {
+	frames := errfmt.Callers(0)
+	if len(frames) == 0 {
+		t.Fatal("Callers returned no frames")
+	}
+
+	if got := frames[0].Function; got != "go.followtheprocess.codes/hue/errfmt_test.TestCallers" {
+		t.Errorf("first frame is %q, wanted TestCallers", got)
+	}
+
+	if !strings.HasSuffix(frames[0].File, "errfmt_test.go") || frames[0].Line == 0 {
+		t.Errorf("unexpected location for first frame: %s:%d", frames[0].File, frames[0].Line)
+	}
+}
+
+func TestStack(t *testing.T) {
+	hue.Enabled(true)
+
+	formatter := errfmt.Formatter{Module: "example.com/app", Own: hue.Bold, Location: hue.Cyan, Other: hue.BrightBlack}
+	frames := errfmt.ParseStack([]byte(stack))[1:4]
+
+	buf := &bytes.Buffer{}
+	if err := formatter.Stack(buf, frames); err != nil {
+		t.Fatalf("Stack returned an unexpected error: %v", err)
+	}
+
+	got := strconv.Quote(buf.String())
+	want := strconv.Quote(
+		"\x1b[1mmain.main.func1\x1b[0m\n" +
+			"    \x1b[36m/tmp/stack.go:11\x1b[0m\n" +
+			"\x1b[1mexample.com/app/internal/db.(*Conn).Query\x1b[0m\n" +
+			"    \x1b[36m/home/me/app/internal/db/conn.go:42\x1b[0m\n" +
+			"\x1b[90msync.(*WaitGroup).Go.func1\x1b[0m\n" +
+			"    \x1b[90m/usr/local/go/src/sync/waitgroup.go:258\x1b[0m\n",
+	)
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	hue.Enabled(false)
+
+	buf := &bytes.Buffer{}
+	if err := (errfmt.Formatter{Module: "example.com/app"}).Panic(buf, "boom", []byte(stack)); err != nil {
+		t.Fatalf("Panic returned an unexpected error: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "panic: boom\n\nruntime/debug.Stack\n") {
+		t.Errorf("unexpected panic output:\n%s", got)
+	}
+
+	buf.Reset()
+
+	if err := (errfmt.Formatter{}).Panic(buf, fmt.Errorf("bad: %w", errors.New("index out of range")), nil); err != nil {
+		t.Fatalf("Panic returned an unexpected error: %v", err)
+	}
+
+	if want := "panic: bad\n└── index out of range\n\n"; buf.String() != want {
+		t.Errorf("\nGot:\t%q\nWanted:\t%q\n", buf.String(), want)
+	}
+
+	buf.Reset()
+
+	formatter := errfmt.Formatter{Renderer: &hue.Renderer{Colour: true}, Message: hue.Red}
+	if err := formatter.Panic(buf, "boom", nil); err != nil {
+		t.Fatalf("Panic returned an unexpected error: %v", err)
+	}
+
+	if want := "\x1b[31mpanic: \x1b[0m\x1b[31mboom\x1b[0m\n\n"; buf.String() != want {
+		t.Errorf("\nGot:\t%q\nWanted:\t%q\n", buf.String(), want)
+	}
+}
@@ -0,0 +1,128 @@
+package hue
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"go.followtheprocess.codes/hue/ansi"
+)
+
+// escapeLen returns the length in bytes of the ANSI escape sequence at the start
+// of s, or 0 if s does not start with one.
+//
+// The end of the sequence is found by an [ansi.Parser], so every kind of sequence
+// (e.g. SGR styles, OSC hyperlinks) is handled. An unterminated sequence runs to the end of s.
+func escapeLen(s string) int {
+	if len(s) == 0 || s[0] != '\x1b' {
+		return 0
+	}
+
+	var p ansi.Parser
+
+	for i := range len(s) {
+		p.WriteByte(s[i]) //nolint: errcheck // Parsing never fails
+
+		if p.State() != ansi.StateGround {
+			continue
+		}
+
+		if s[i] >= utf8.RuneSelf {
+			// Text can't be part of a sequence so ends it
+			return i
+		}
+
+		return i + 1
+	}
+
+	return len(s)
+}
+
+// visibleWidth returns the width of s as shown on a terminal, that is the number of
+// runes in s excluding any ANSI escape sequences.
+//
+// Like [go.followtheprocess.codes/hue/tabwriter], it assumes every rune has a width of 1.
+func visibleWidth(s string) int {
+	width := 0
+
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		width++
+	}
+
+	return width
+}
+
+// isSGR reports whether seq, a complete escape sequence, sets graphic rendition
+// i.e. is a style.
+func isSGR(seq string) bool {
+	return len(seq) >= 3 && seq[1] == '[' && seq[len(seq)-1] == 'm'
+}
+
+// isReset reports whether seq, a complete SGR sequence, resets all styles.
+func isReset(seq string) bool {
+	return seq == reset || seq == escape+"m"
+}
+
+// isHyperlink reports whether seq, a complete escape sequence, is an OSC 8 hyperlink
+// and if so, whether it opens a link rather than closing one.
+func isHyperlink(seq string) (link, open bool) {
+	rest, ok := strings.CutPrefix(seq, linkOpen)
+	if !ok {
+		return false, false
+	}
+
+	// The parameters, then the URI up to the terminator
+	_, uri, _ := strings.Cut(rest, ";")
+	uri = strings.TrimSuffix(strings.TrimSuffix(uri, "\x1b\\"), "\a")
+
+	return true, uri != ""
+}
+
+// splitLines splits s into lines on '\n', making each line self contained with
+// respect to styling: any style still open at the end of a line is reset there,
+// and re-opened at the start of the next.
+//
+// This allows styled text to be placed alongside other content (e.g. a border,
+// indentation or another column) without the style bleeding into it.
+func splitLines(s string) []string {
+	raw := strings.Split(s, "\n")
+	lines := make([]string, 0, len(raw))
+
+	var active strings.Builder // SGR sequences in effect at the start of the current line
+
+	for _, line := range raw {
+		open := active.String()
+
+		for i := 0; i < len(line); {
+			n := escapeLen(line[i:])
+			if n == 0 {
+				i++
+				continue
+			}
+
+			if seq := line[i : i+n]; isSGR(seq) {
+				if isReset(seq) {
+					active.Reset()
+				} else {
+					active.WriteString(seq)
+				}
+			}
+
+			i += n
+		}
+
+		if active.Len() != 0 {
+			line += reset
+		}
+
+		lines = append(lines, open+line)
+	}
+
+	return lines
+}
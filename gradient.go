@@ -0,0 +1,224 @@
+package hue
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Interpolation is the colour space in which the colours of a gradient are blended.
+type Interpolation int
+
+const (
+	// OKLab blends colours in the perceptually uniform OKLab colour space, giving smooth
+	// gradients without the muddy or overly dark midpoints of RGB. It is the default.
+	OKLab Interpolation = iota
+
+	// LinearRGB blends each of the red, green and blue channels independently in linear
+	// light, as mixing light does, rather than in gamma encoded sRGB.
+	LinearRGB
+)
+
+// Gradient returns text with its foreground colour blended smoothly from one colour
+// to another, character by character, in the [OKLab] colour space.
+//
+// It is shorthand for:
+//
+//	hue.MultiGradient(text, hue.OKLab, from, to)
+//
+// See [MultiGradient] for details.
+func Gradient(text string, from, to RGB) string {
+	return DefaultRenderer().MultiGradient(text, OKLab, from, to)
+}
+
+// MultiGradient returns text with its foreground colour blended smoothly through each
+// of the given colour stops in turn, character by character, the stops are evenly spaced
+// across the width of the text.
+//
+// Multi-line text is treated as a block: the gradient runs horizontally across the width
+// of the longest line so each column of characters has the same colour.
+//
+// When the terminal doesn't support truecolour, each character is given the nearest
+// colour it does support, see [ColourLevel]. If colour is disabled, text is returned unchanged.
+func MultiGradient(text string, interpolation Interpolation, stops ...RGB) string {
+	return DefaultRenderer().MultiGradient(text, interpolation, stops...)
+}
+
+// Gradient is like the package level [Gradient] but renders with the settings of r.
+func (r Renderer) Gradient(text string, from, to RGB) string {
+	return r.MultiGradient(text, OKLab, from, to)
+}
+
+// MultiGradient is like the package level [MultiGradient] but renders with the settings of r.
+func (r Renderer) MultiGradient(text string, interpolation Interpolation, stops ...RGB) string {
+	if !r.Colour || len(stops) == 0 || text == "" {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, visibleWidth(line))
+	}
+
+	var (
+		dst  = make([]byte, 0, len(text)) // Grown as escapes are added, as how many depends on the colours
+		code []byte                       // code of the colour currently in effect
+		next []byte                       // scratch buffer for the code of the next character
+	)
+
+	for i, line := range lines {
+		if i > 0 {
+			dst = append(dst, '\n')
+		}
+
+		column := 0
+
+		for j := 0; j < len(line); {
+			if n := escapeLen(line[j:]); n > 0 {
+				seq := line[j : j+n]
+				dst = append(dst, seq...)
+				j += n
+
+				if isReset(seq) {
+					// The text reset our colour too, so it must be emitted again
+					code = code[:0]
+				}
+
+				continue
+			}
+
+			char, size := utf8.DecodeRuneInString(line[j:])
+
+			// Only bother colouring characters you can see
+			if !unicode.IsSpace(char) {
+				t := 0.0
+				if width > 1 {
+					t = float64(column) / float64(width-1)
+				}
+
+				next = blend(stops, t, interpolation).appendCode(next[:0], false, r.Level, r.Simulation)
+				if string(next) != string(code) {
+					// Colour changed, emit a new escape
+					dst = append(dst, escape...)
+					dst = append(dst, next...)
+					dst = append(dst, 'm')
+					code = append(code[:0], next...)
+				}
+			}
+
+			dst = append(dst, line[j:j+size]...)
+			j += size
+			column++
+		}
+
+		if len(code) != 0 {
+			dst = append(dst, reset...)
+			code = code[:0]
+		}
+	}
+
+	return string(dst)
+}
+
+// blend returns the colour at position t (from 0 to 1) along a gradient through
+// evenly spaced stops.
+func blend(stops []RGB, t float64, interpolation Interpolation) RGB {
+	if len(stops) == 1 {
+		return stops[0]
+	}
+
+	segments := float64(len(stops) - 1)
+	i := min(int(t*segments), len(stops)-2) //nolint: mnd // Index of the first stop of the last segment
+	local := t*segments - float64(i)
+
+	return mix(stops[i], stops[i+1], local, interpolation)
+}
+
+// mix returns the colour a fraction t (from 0 to 1) of the way from a to b.
+func mix(a, b RGB, t float64, interpolation Interpolation) RGB {
+	if interpolation == LinearRGB {
+		return RGB{
+			R: lerpLinear(a.R, b.R, t),
+			G: lerpLinear(a.G, b.G, t),
+			B: lerpLinear(a.B, b.B, t),
+		}
+	}
+
+	la, lb := toOKLab(a), toOKLab(b)
+
+	return oklab{
+		L: la.L + (lb.L-la.L)*t,
+		A: la.A + (lb.A-la.A)*t,
+		B: la.B + (lb.B-la.B)*t,
+	}.rgb()
+}
+
+// lerpLinear linearly interpolates a single 8 bit sRGB colour channel in linear light.
+func lerpLinear(a, b uint8, t float64) uint8 {
+	la, lb := toLinear(a), toLinear(b)
+	return fromLinear(la + (lb-la)*t)
+}
+
+// oklab is a colour in the OKLab colour space.
+//
+// See https://bottosson.github.io/posts/oklab/.
+type oklab struct {
+	L float64 // Perceived lightness
+	A float64 // Green/red
+	B float64 // Blue/yellow
+}
+
+// toOKLab converts an sRGB colour to OKLab.
+func toOKLab(c RGB) oklab {
+	r, g, b := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return oklab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// rgb converts an OKLab colour to sRGB, clamping it to the sRGB gamut.
+func (c oklab) rgb() RGB {
+	l := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	m := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	s := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return RGB{
+		R: fromLinear(+4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		G: fromLinear(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		B: fromLinear(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s),
+	}
+}
+
+// toLinear converts an 8 bit sRGB channel to linear light in the range 0 to 1.
+func toLinear(channel uint8) float64 {
+	c := float64(channel) / 255
+	if c <= 0.04045 { //nolint: mnd // sRGB transfer function
+		return c / 12.92 //nolint: mnd // sRGB transfer function
+	}
+
+	return math.Pow((c+0.055)/1.055, 2.4) //nolint: mnd // sRGB transfer function
+}
+
+// fromLinear converts a linear light value in the range 0 to 1 to an 8 bit sRGB
+// channel, clamping out of range values.
+func fromLinear(c float64) uint8 {
+	if c <= 0.0031308 { //nolint: mnd // sRGB transfer function
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055 //nolint: mnd // sRGB transfer function
+	}
+
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255)) //nolint: mnd // 8 bit channel
+}
@@ -0,0 +1,165 @@
+package hue_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestGradient(t *testing.T) {
+	var (
+		black = hue.RGB{R: 0, G: 0, B: 0}
+		white = hue.RGB{R: 255, G: 255, B: 255}
+		red   = hue.RGB{R: 255, G: 0, B: 0}
+		blue  = hue.RGB{R: 0, G: 0, B: 255}
+	)
+
+	tests := []struct {
+		name          string            // Name of the test case
+		text          string            // Text to colour
+		want          string            // Expected result
+		stops         []hue.RGB         // Colour stops
+		interpolation hue.Interpolation // Colour space to blend in
+		level         hue.ColourLevel   // Colour level of the terminal
+		enabled       bool              // Whether hue is enabled
+	}{
+		{
+			name:          "linear rgb",
+			text:          "abc",
+			stops:         []hue.RGB{black, white},
+			interpolation: hue.LinearRGB,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			// Mid grey in linear light is lighter than the sRGB midpoint
+			want: "\x1b[38;2;0;0;0ma\x1b[38;2;188;188;188mb\x1b[38;2;255;255;255mc\x1b[0m",
+		},
+		{
+			name:          "oklab",
+			text:          "abc",
+			stops:         []hue.RGB{black, white},
+			interpolation: hue.OKLab,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			// Perceptual mid grey is lighter than the RGB midpoint
+			want: "\x1b[38;2;0;0;0ma\x1b[38;2;99;99;99mb\x1b[38;2;255;255;255mc\x1b[0m",
+		},
+		{
+			name:          "multi stop",
+			text:          "abc",
+			stops:         []hue.RGB{red, white, blue},
+			interpolation: hue.LinearRGB,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			want:          "\x1b[38;2;255;0;0ma\x1b[38;2;255;255;255mb\x1b[38;2;0;0;255mc\x1b[0m",
+		},
+		{
+			name:          "spaces are not coloured",
+			text:          "a b",
+			stops:         []hue.RGB{black, white},
+			interpolation: hue.LinearRGB,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			want:          "\x1b[38;2;0;0;0ma \x1b[38;2;255;255;255mb\x1b[0m",
+		},
+		{
+			name:          "downgraded to 16 colours merges runs",
+			text:          "aaaa",
+			stops:         []hue.RGB{red, {R: 250, G: 5, B: 5}},
+			interpolation: hue.LinearRGB,
+			level:         hue.Level16,
+			enabled:       true,
+			want:          "\x1b[91maaaa\x1b[0m",
+		},
+		{
+			name:          "downgraded to 256 colours",
+			text:          "ab",
+			stops:         []hue.RGB{red, blue},
+			interpolation: hue.LinearRGB,
+			level:         hue.Level256,
+			enabled:       true,
+			want:          "\x1b[38;5;196ma\x1b[38;5;21mb\x1b[0m",
+		},
+		{
+			name:          "multi line block",
+			text:          "abc\nd\nef",
+			stops:         []hue.RGB{black, white},
+			interpolation: hue.LinearRGB,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			want: "\x1b[38;2;0;0;0ma\x1b[38;2;188;188;188mb\x1b[38;2;255;255;255mc\x1b[0m\n" +
+				"\x1b[38;2;0;0;0md\x1b[0m\n" +
+				"\x1b[38;2;0;0;0me\x1b[38;2;188;188;188mf\x1b[0m",
+		},
+		{
+			name:          "single stop",
+			text:          "ab",
+			stops:         []hue.RGB{red},
+			interpolation: hue.OKLab,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			want:          "\x1b[38;2;255;0;0mab\x1b[0m",
+		},
+		{
+			name:          "colour restored after a reset",
+			text:          "a\x1b[0mb",
+			stops:         []hue.RGB{red},
+			interpolation: hue.OKLab,
+			level:         hue.LevelTrueColour,
+			enabled:       true,
+			want:          "\x1b[38;2;255;0;0ma\x1b[0m\x1b[38;2;255;0;0mb\x1b[0m",
+		},
+		{
+			name:          "disabled",
+			text:          "abc",
+			stops:         []hue.RGB{black, white},
+			interpolation: hue.OKLab,
+			level:         hue.LevelTrueColour,
+			enabled:       false,
+			want:          "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(tt.enabled)
+			hue.SetColourLevel(tt.level)
+
+			got := strconv.Quote(hue.MultiGradient(tt.text, tt.interpolation, tt.stops...))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestGradientEndpoints(t *testing.T) {
+	hue.Enabled(true)
+	hue.SetColourLevel(hue.LevelTrueColour)
+
+	from := hue.RGB{R: 12, G: 200, B: 99}
+	to := hue.RGB{R: 240, G: 3, B: 180}
+
+	got := hue.Gradient(strings.Repeat("x", 10), from, to)
+
+	if !strings.HasPrefix(got, "\x1b[38;2;12;200;99m") {
+		t.Errorf("gradient did not start with from colour: %q", got)
+	}
+
+	if !strings.HasSuffix(got, "\x1b[38;2;240;3;180mx\x1b[0m") {
+		t.Errorf("gradient did not end with to colour: %q", got)
+	}
+}
+
+func TestVisualGradient(t *testing.T) {
+	hue.Enabled(true) // go test buffers output so autodetection disabled colour
+	hue.SetColourLevel(hue.LevelTrueColour)
+
+	// Run with go test -v, simple visual check to see if we're writing the correct colours
+	banner := strings.Repeat("█", 60)
+	t.Log("\n" + hue.MultiGradient(banner+"\n"+banner, hue.OKLab, hue.RGB{R: 255, G: 0, B: 128}, hue.RGB{R: 0, G: 128, B: 255}))
+	t.Log("\n" + hue.MultiGradient(banner+"\n"+banner, hue.LinearRGB, hue.RGB{R: 255, G: 0, B: 128}, hue.RGB{R: 0, G: 128, B: 255}))
+}
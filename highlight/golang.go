@@ -0,0 +1,106 @@
+package highlight
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// goTokeniser tokenises Go source with [go/scanner], buffering it all until flushed.
+type goTokeniser struct {
+	emit emitFunc
+	src  strings.Builder // Source written so far
+}
+
+func (t *goTokeniser) write(src string) {
+	t.src.WriteString(src)
+}
+
+func (t *goTokeniser) flush() {
+	src := t.src.String()
+	t.src.Reset()
+
+	if src == "" {
+		return
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments) // Errors are ignored, invalid source is passed through
+
+	last := 0
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		if tok == token.SEMICOLON && lit != ";" {
+			continue // Automatically inserted at a newline or EOF
+		}
+
+		start := file.Offset(pos)
+
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+
+		end := min(start+len(text), len(src))
+
+		// Comment literals have any carriage returns removed, take the text from src to
+		// preserve them
+		if tok == token.COMMENT && strings.HasPrefix(text, "/*") {
+			if i := strings.Index(src[start:], "*/"); i != -1 {
+				end = start + i + len("*/")
+			}
+		}
+
+		if start < last {
+			continue // Overlaps a token we have already emitted, only possible for invalid source
+		}
+
+		if start > last {
+			t.emit(Plain, src[last:start])
+		}
+
+		t.emit(goKind(tok, lit), src[start:end])
+		last = end
+	}
+
+	if last < len(src) {
+		t.emit(Plain, src[last:])
+	}
+}
+
+// goKind returns the kind of a Go token.
+func goKind(tok token.Token, lit string) Kind {
+	switch {
+	case tok == token.COMMENT:
+		return Comment
+	case tok == token.STRING || tok == token.CHAR:
+		return String
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return Number
+	case tok.IsKeyword():
+		return Keyword
+	case tok.IsOperator():
+		return Punctuation
+	case tok == token.IDENT:
+		switch lit {
+		case "true", "false":
+			return Bool
+		case "nil":
+			return Null
+		case "any", "bool", "byte", "comparable", "complex64", "complex128", "error", "float32", "float64",
+			"int", "int8", "int16", "int32", "int64", "rune", "string",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+			return Type
+		}
+	}
+
+	return Plain
+}
@@ -0,0 +1,268 @@
+// Package highlight provides lightweight syntax highlighting of JSON, YAML and Go snippets
+// with [hue.Style]s, for printing API responses, config fragments or code in a CLI.
+//
+// Source is split into tokens of a [Kind] (keys, strings, numbers etc.) and each is styled
+// according to a [Theme]:
+//
+//	h := highlight.Highlighter{Language: highlight.JSON, Theme: highlight.DefaultTheme, Indent: "  "}
+//	fmt.Print(h.Text(`{"name":"hue","stars":42}`))
+//
+// Produces (in colour):
+//
+//	{
+//	  "name": "hue",
+//	  "stars": 42
+//	}
+//
+// The highlighters are deliberately forgiving: they never fail on invalid input, they just
+// do the best they can, so they are suitable for showing malformed input in error messages.
+package highlight // import "go.followtheprocess.codes/hue/highlight"
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.followtheprocess.codes/hue"
+)
+
+// Language is a language that can be highlighted.
+type Language int
+
+const (
+	JSON Language = iota // JSON, including a stream of JSON values (e.g. newline delimited JSON)
+	YAML                 // YAML
+	Go                   // Go source code, or fragments of it
+)
+
+// String implements [fmt.Stringer] for a [Language].
+func (l Language) String() string {
+	switch l {
+	case JSON:
+		return "JSON"
+	case YAML:
+		return "YAML"
+	case Go:
+		return "Go"
+	default:
+		return fmt.Sprintf("Language(%d)", int(l))
+	}
+}
+
+// Kind is the kind of a [Token].
+type Kind int
+
+const (
+	Plain       Kind = iota // Whitespace, identifiers and anything not otherwise recognised
+	Key                     // Object or mapping keys
+	String                  // String literals, and plain scalars in YAML
+	Number                  // Number literals
+	Bool                    // true and false
+	Null                    // null in JSON and YAML, nil in Go
+	Punctuation             // Brackets, separators and operators
+	Comment                 // Comments
+	Keyword                 // Go keywords, YAML anchors, aliases and tags
+	Type                    // Go's predeclared types
+)
+
+// String implements [fmt.Stringer] for a [Kind].
+func (k Kind) String() string {
+	switch k {
+	case Plain:
+		return "Plain"
+	case Key:
+		return "Key"
+	case String:
+		return "String"
+	case Number:
+		return "Number"
+	case Bool:
+		return "Bool"
+	case Null:
+		return "Null"
+	case Punctuation:
+		return "Punctuation"
+	case Comment:
+		return "Comment"
+	case Keyword:
+		return "Keyword"
+	case Type:
+		return "Type"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Token is a single token of highlighted source.
+type Token struct {
+	Text string // The source text of the token
+	Kind Kind   // What kind of token it is
+}
+
+// Theme is the set of styles to highlight each [Kind] of token with.
+type Theme struct {
+	Key         hue.Style // Style for Key tokens
+	String      hue.Style // Style for String tokens
+	Number      hue.Style // Style for Number tokens
+	Bool        hue.Style // Style for Bool tokens
+	Null        hue.Style // Style for Null tokens
+	Punctuation hue.Style // Style for Punctuation tokens
+	Comment     hue.Style // Style for Comment tokens
+	Keyword     hue.Style // Style for Keyword tokens
+	Type        hue.Style // Style for Type tokens
+}
+
+// DefaultTheme is a [Theme] using the basic colours, so it works on any terminal.
+var DefaultTheme = Theme{
+	Key:         hue.Blue | hue.Bold,
+	String:      hue.Green,
+	Number:      hue.Cyan,
+	Bool:        hue.Yellow,
+	Null:        hue.Magenta,
+	Punctuation: hue.BrightBlack,
+	Comment:     hue.BrightBlack | hue.Italic,
+	Keyword:     hue.Magenta | hue.Bold,
+	Type:        hue.Cyan | hue.Bold,
+}
+
+// Style returns the style for tokens of kind k, Plain tokens are never styled.
+func (t Theme) Style(k Kind) hue.Style {
+	switch k {
+	case Key:
+		return t.Key
+	case String:
+		return t.String
+	case Number:
+		return t.Number
+	case Bool:
+		return t.Bool
+	case Null:
+		return t.Null
+	case Punctuation:
+		return t.Punctuation
+	case Comment:
+		return t.Comment
+	case Keyword:
+		return t.Keyword
+	case Type:
+		return t.Type
+	default:
+		return 0
+	}
+}
+
+// Tokens splits src into tokens of the given language. Concatenating the text of
+// every token gives back src.
+func Tokens(lang Language, src string) []Token {
+	var tokens []Token
+
+	t := newTokeniser(lang, "", func(kind Kind, text string) {
+		tokens = append(tokens, Token{Kind: kind, Text: text})
+	})
+	t.write(src)
+	t.flush()
+
+	return tokens
+}
+
+// Highlighter highlights source code. The zero value highlights JSON without any styles.
+type Highlighter struct {
+	Renderer *hue.Renderer // Renders the Theme's styles, nil uses the package wide settings
+	Indent   string        // If not empty, pretty print JSON indenting with Indent, ignored for other languages
+	Theme    Theme         // Styles for each kind of token
+	Language Language      // Language of the source
+}
+
+// Text returns src highlighted.
+func (h Highlighter) Text(src string) string {
+	s := &strings.Builder{}
+
+	w := h.Writer(s)
+	w.Write([]byte(src)) //nolint: errcheck // Writes to a strings.Builder cannot fail
+	w.Flush()            //nolint: errcheck // Writes to a strings.Builder cannot fail
+
+	return s.String()
+}
+
+// Writer returns a [Writer] that highlights everything written to it before writing
+// it to out.
+func (h Highlighter) Writer(out io.Writer) *Writer {
+	renderer := hue.DefaultRenderer()
+	if h.Renderer != nil {
+		renderer = *h.Renderer
+	}
+
+	w := &Writer{out: out}
+	w.tokeniser = newTokeniser(h.Language, h.Indent, func(kind Kind, text string) {
+		w.dst = renderer.AppendString(w.dst, h.Theme.Style(kind), text)
+	})
+
+	return w
+}
+
+// Writer is an [io.Writer] that highlights source written to it before passing it on to
+// the underlying writer, see [Highlighter].
+//
+// JSON is highlighted as it is written, a token at a time. YAML is highlighted a line at a
+// time. Go is buffered until [Writer.Flush] as comments and raw strings can span many lines.
+// Callers must always call Flush when done writing to ensure everything is written.
+type Writer struct {
+	out       io.Writer // Destination
+	tokeniser tokeniser // Language specific tokeniser
+	dst       []byte    // Highlighted output pending a write to out
+}
+
+// Write highlights p, writing as much as possible to the underlying writer.
+//
+// It returns len(p) and a nil error unless the underlying writer returned an error.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.tokeniser.write(string(p))
+
+	if err := w.drain(); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush highlights and writes any source still buffered to the underlying writer.
+func (w *Writer) Flush() error {
+	w.tokeniser.flush()
+	return w.drain()
+}
+
+// drain writes the highlighted output so far to the underlying writer.
+func (w *Writer) drain() error {
+	if len(w.dst) == 0 {
+		return nil
+	}
+
+	_, err := w.out.Write(w.dst)
+	w.dst = w.dst[:0]
+
+	return err
+}
+
+// emitFunc receives each token from a tokeniser.
+type emitFunc func(kind Kind, text string)
+
+// tokeniser splits source into tokens, calling emit for each.
+type tokeniser interface {
+	// write adds src, emitting every token that is now known to be complete.
+	write(src string)
+
+	// flush emits everything that remains.
+	flush()
+}
+
+// newTokeniser returns a tokeniser for lang, indent is used for pretty printing JSON.
+func newTokeniser(lang Language, indent string, emit emitFunc) tokeniser {
+	switch lang {
+	case YAML:
+		return &yamlTokeniser{emit: emit}
+	case Go:
+		return &goTokeniser{emit: emit}
+	default:
+		return &jsonTokeniser{emit: emit, indent: indent}
+	}
+}
@@ -0,0 +1,312 @@
+package highlight_test
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/highlight"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name string             // Name of the test case
+		src  string             // Source to tokenise
+		want []highlight.Token  // Expected tokens
+		lang highlight.Language // Language of src
+	}{
+		{
+			name: "json",
+			lang: highlight.JSON,
+			src:  `{"a": [1.5e3, "x", true, null]}`,
+			want: []highlight.Token{
+				{Kind: highlight.Punctuation, Text: "{"},
+				{Kind: highlight.Key, Text: `"a"`},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: "["},
+				{Kind: highlight.Number, Text: "1.5e3"},
+				{Kind: highlight.Punctuation, Text: ","},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.String, Text: `"x"`},
+				{Kind: highlight.Punctuation, Text: ","},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Bool, Text: "true"},
+				{Kind: highlight.Punctuation, Text: ","},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Null, Text: "null"},
+				{Kind: highlight.Punctuation, Text: "]"},
+				{Kind: highlight.Punctuation, Text: "}"},
+			},
+		},
+		{
+			name: "json escaped quote",
+			lang: highlight.JSON,
+			src:  `["a\"b"]`,
+			want: []highlight.Token{
+				{Kind: highlight.Punctuation, Text: "["},
+				{Kind: highlight.String, Text: `"a\"b"`},
+				{Kind: highlight.Punctuation, Text: "]"},
+			},
+		},
+		{
+			name: "yaml",
+			lang: highlight.YAML,
+			src:  "- name: hue # the name\n  stars: 42\n",
+			want: []highlight.Token{
+				{Kind: highlight.Punctuation, Text: "-"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Key, Text: "name"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.String, Text: "hue"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Comment, Text: "# the name"},
+				{Kind: highlight.Plain, Text: "\n"},
+				{Kind: highlight.Plain, Text: "  "},
+				{Kind: highlight.Key, Text: "stars"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Number, Text: "42"},
+				{Kind: highlight.Plain, Text: "\n"},
+			},
+		},
+		{
+			name: "yaml block scalar",
+			lang: highlight.YAML,
+			src:  "run: |\n  go test: ./...\nnext: ~",
+			want: []highlight.Token{
+				{Kind: highlight.Key, Text: "run"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: "|"},
+				{Kind: highlight.Plain, Text: "\n"},
+				{Kind: highlight.String, Text: "  go test: ./..."},
+				{Kind: highlight.Plain, Text: "\n"},
+				{Kind: highlight.Key, Text: "next"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Null, Text: "~"},
+			},
+		},
+		{
+			name: "yaml flow",
+			lang: highlight.YAML,
+			src:  "x: {a: [1, 'b'], *c : !!str d}",
+			want: []highlight.Token{
+				{Kind: highlight.Key, Text: "x"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: "{"},
+				{Kind: highlight.Key, Text: "a"},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: "["},
+				{Kind: highlight.Number, Text: "1"},
+				{Kind: highlight.Punctuation, Text: ","},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.String, Text: "'b'"},
+				{Kind: highlight.Punctuation, Text: "]"},
+				{Kind: highlight.Punctuation, Text: ","},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Keyword, Text: "*c"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: ":"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Keyword, Text: "!!str"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.String, Text: "d"},
+				{Kind: highlight.Punctuation, Text: "}"},
+			},
+		},
+		{
+			name: "go",
+			lang: highlight.Go,
+			src:  "var x error = nil // none\n",
+			want: []highlight.Token{
+				{Kind: highlight.Keyword, Text: "var"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Plain, Text: "x"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Type, Text: "error"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Punctuation, Text: "="},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Null, Text: "nil"},
+				{Kind: highlight.Plain, Text: " "},
+				{Kind: highlight.Comment, Text: "// none"},
+				{Kind: highlight.Plain, Text: "\n"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlight.Tokens(tt.lang, tt.src)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("\nGot:\t%+v\nWanted:\t%+v\n", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensRoundTrip(t *testing.T) {
+	sources := map[highlight.Language][]string{
+		highlight.JSON: {
+			`{"a":1}`,
+			"[1, 2,\n 3]\n{\"b\": \"c\"}\n",
+			`{"unterminated": "str`,
+			`not json at all: {]`,
+		},
+		highlight.YAML: {
+			"a: 1\nb:\n  - c\n  - 'd: e'\n",
+			"key: \"unterminated\n",
+			"--- # doc\nfold: >+2\n   text\n...\n",
+		},
+		highlight.Go: {
+			"package main\n\nfunc main() {\n\tprintln(`raw\nstring`) /* block\r\ncomment */\n}\n",
+			"x := 'a' + 1.5i",
+			"invalid $ source @",
+		},
+	}
+
+	for lang, srcs := range sources {
+		for _, src := range srcs {
+			var got strings.Builder
+			for _, tok := range highlight.Tokens(lang, src) {
+				got.WriteString(tok.Text)
+			}
+
+			if got.String() != src {
+				t.Errorf("%v tokens of %q don't round trip, got %q", lang, src, got.String())
+			}
+		}
+	}
+}
+
+func TestHighlighter(t *testing.T) {
+	tests := []struct {
+		name        string                // Name of the test case
+		src         string                // Source to highlight
+		want        string                // Expected output
+		highlighter highlight.Highlighter // Highlighter under test
+	}{
+		{
+			name:        "pretty json",
+			highlighter: highlight.Highlighter{Language: highlight.JSON, Indent: "  "},
+			src:         `{"name":"hue","tags":["a", 1],"empty":{},"none":[]} 42`,
+			want: "{\n" +
+				"  \"name\": \"hue\",\n" +
+				"  \"tags\": [\n" +
+				"    \"a\",\n" +
+				"    1\n" +
+				"  ],\n" +
+				"  \"empty\": {},\n" +
+				"  \"none\": []\n" +
+				"}\n" +
+				"42\n",
+		},
+		{
+			name:        "styled json",
+			highlighter: highlight.Highlighter{Language: highlight.JSON, Theme: highlight.DefaultTheme},
+			src:         `{"a": true}`,
+			want:        "\x1b[90m{\x1b[0m\x1b[1;34m\"a\"\x1b[0m\x1b[90m:\x1b[0m \x1b[33mtrue\x1b[0m\x1b[90m}\x1b[0m",
+		},
+		{
+			name: "styled json plain renderer",
+			highlighter: highlight.Highlighter{
+				Renderer: &hue.Renderer{},
+				Language: highlight.JSON,
+				Theme:    highlight.DefaultTheme,
+			},
+			src:  `{"a": true}`,
+			want: `{"a": true}`,
+		},
+		{
+			name:        "styled yaml",
+			highlighter: highlight.Highlighter{Language: highlight.YAML, Theme: highlight.Theme{Key: hue.Blue, Number: hue.Cyan}},
+			src:         "port: 8080\n",
+			want:        "\x1b[34mport\x1b[0m: \x1b[36m8080\x1b[0m\n",
+		},
+		{
+			name:        "styled go",
+			highlighter: highlight.Highlighter{Language: highlight.Go, Theme: highlight.Theme{Keyword: hue.Magenta, String: hue.Green}},
+			src:         `return "ok"`,
+			want:        "\x1b[35mreturn\x1b[0m \x1b[32m\"ok\"\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+
+			got := strconv.Quote(tt.highlighter.Text(tt.src))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestWriterStreaming(t *testing.T) {
+	sources := map[highlight.Language]string{
+		highlight.JSON: `{"name": "hue", "escaped": "a\"b\\", "n": -1.5e-3, "ok": false, "nothing": null}` + "\n[1]",
+		highlight.YAML: "name: hue\nlist: [1, 2]\nblock: |\n  text\n",
+		highlight.Go:   "func f() string {\n\treturn `raw` // done\n}\n",
+	}
+
+	for lang, src := range sources {
+		for _, indent := range []string{"", "\t"} {
+			h := highlight.Highlighter{Language: lang, Theme: highlight.DefaultTheme, Indent: indent}
+			want := h.Text(src)
+
+			// Writing a byte at a time must give the same result as all at once
+			buf := &bytes.Buffer{}
+			w := h.Writer(buf)
+
+			for i := range len(src) {
+				if _, err := w.Write([]byte{src[i]}); err != nil {
+					t.Fatalf("Write returned an unexpected error: %v", err)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush returned an unexpected error: %v", err)
+			}
+
+			if got := buf.String(); got != want {
+				t.Errorf("%v (indent %q) streamed a byte at a time:\nGot:\t%q\nWanted:\t%q\n", lang, indent, got, want)
+			}
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for lang, want := range map[highlight.Language]string{
+		highlight.JSON:        "JSON",
+		highlight.YAML:        "YAML",
+		highlight.Go:          "Go",
+		highlight.Language(9): "Language(9)",
+	} {
+		if got := lang.String(); got != want {
+			t.Errorf("Language(%d).String() = %q, wanted %q", int(lang), got, want)
+		}
+	}
+
+	for kind, want := range map[highlight.Kind]string{
+		highlight.Plain:       "Plain",
+		highlight.Key:         "Key",
+		highlight.Punctuation: "Punctuation",
+		highlight.Type:        "Type",
+		highlight.Kind(42):    "Kind(42)",
+	} {
+		if got := kind.String(); got != want {
+			t.Errorf("Kind(%d).String() = %q, wanted %q", int(kind), got, want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package highlight
+
+import "strings"
+
+// jsonTokeniser tokenises JSON incrementally, optionally pretty printing it.
+type jsonTokeniser struct {
+	emit        emitFunc
+	indent      string // Indent to pretty print with, "" to leave formatting as is
+	pending     string // Source of a token that may continue in the next write
+	stack       []byte // Open containers, '{' or '['
+	expectKey   bool   // Whether the next string is an object key
+	pendingOpen bool   // Whether a container has been opened but its first element not yet written
+}
+
+func (t *jsonTokeniser) write(src string) {
+	t.pending = t.scan(t.pending+src, false)
+}
+
+func (t *jsonTokeniser) flush() {
+	t.pending = t.scan(t.pending, true)
+}
+
+// scan emits the tokens in src, returning any incomplete token at the end. If final is
+// true, the input is complete so everything is emitted.
+func (t *jsonTokeniser) scan(src string, final bool) string {
+	for len(src) > 0 {
+		n, kind, complete := jsonToken(src)
+		if !complete && !final {
+			// May be cut short e.g. a string missing its closing quote, or "tr" of "true"
+			return src
+		}
+
+		t.token(kind, src[:n])
+		src = src[n:]
+	}
+
+	return ""
+}
+
+// jsonToken returns the length and kind of the token at the start of src, and whether
+// it is known to be complete, which a token running to the end of src may not be.
+func jsonToken(src string) (n int, kind Kind, complete bool) {
+	n, kind = jsonTokenLen(src)
+
+	switch {
+	case n < len(src):
+		return n, kind, true
+	case kind == Punctuation:
+		return n, kind, true
+	case kind == String:
+		return n, kind, n > 1 && src[n-1] == '"' && !escaped(src[:n-1])
+	default:
+		return n, kind, false
+	}
+}
+
+// escaped reports whether s ends in an odd number of backslashes, so that whatever
+// follows it is escaped.
+func escaped(s string) bool {
+	return (len(s)-len(strings.TrimRight(s, `\`)))%2 == 1
+}
+
+// jsonTokenLen returns the length and kind of the token at the start of src.
+func jsonTokenLen(src string) (int, Kind) {
+	switch c := src[0]; {
+	case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		return len(src) - len(strings.TrimLeft(src, " \t\n\r")), Plain
+	case c == '{' || c == '}' || c == '[' || c == ']' || c == ',' || c == ':':
+		return 1, Punctuation
+	case c == '"':
+		for i := 1; i < len(src); i++ {
+			switch src[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1, String
+			}
+		}
+
+		return len(src), String
+	case c == '-' || ('0' <= c && c <= '9'):
+		return span(src, func(c byte) bool {
+			return ('0' <= c && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+		}), Number
+	case 'a' <= c && c <= 'z':
+		n := span(src, func(c byte) bool { return 'a' <= c && c <= 'z' })
+		switch src[:n] {
+		case "true", "false":
+			return n, Bool
+		case "null":
+			return n, Null
+		default:
+			return n, Plain
+		}
+	default:
+		// Invalid, pass through up to the next thing we recognise
+		return max(span(src, func(c byte) bool { return !strings.ContainsRune(" \t\n\r{}[],:\"", rune(c)) }), 1), Plain
+	}
+}
+
+// span returns the length of the prefix of src whose bytes all satisfy ok.
+func span(src string, ok func(c byte) bool) int {
+	i := 0
+	for i < len(src) && ok(src[i]) {
+		i++
+	}
+
+	return i
+}
+
+// token handles a single complete token, tracking structure to tell keys from other strings
+// and pretty printing if enabled.
+func (t *jsonTokeniser) token(kind Kind, text string) {
+	pretty := t.indent != ""
+
+	if kind == Plain && strings.TrimLeft(text, " \t\n\r") == "" {
+		if !pretty {
+			t.emit(Plain, text)
+		}
+
+		return
+	}
+
+	if kind != Punctuation {
+		if kind == String && t.expectKey {
+			kind = Key
+		}
+
+		t.beforeValue()
+		t.emit(kind, text)
+
+		if kind != Key {
+			t.afterValue()
+		}
+
+		return
+	}
+
+	switch text {
+	case "{", "[":
+		t.beforeValue()
+		t.emit(Punctuation, text)
+		t.stack = append(t.stack, text[0])
+		t.expectKey = text == "{"
+		t.pendingOpen = pretty
+	case "}", "]":
+		if len(t.stack) > 0 {
+			t.stack = t.stack[:len(t.stack)-1]
+		}
+
+		if t.pendingOpen {
+			// Empty container, keep it on one line
+			t.pendingOpen = false
+		} else {
+			t.newline()
+		}
+
+		t.emit(Punctuation, text)
+		t.expectKey = false
+		t.afterValue()
+	case ",":
+		t.emit(Punctuation, text)
+		t.newline()
+		t.expectKey = len(t.stack) > 0 && t.stack[len(t.stack)-1] == '{'
+	case ":":
+		t.emit(Punctuation, text)
+		t.expectKey = false
+
+		if pretty {
+			t.emit(Plain, " ")
+		}
+	}
+}
+
+// beforeValue starts the first line of a container, if pretty printing.
+func (t *jsonTokeniser) beforeValue() {
+	if t.pendingOpen {
+		t.pendingOpen = false
+		t.newline()
+	}
+}
+
+// afterValue ends the line after a complete top level value, if pretty printing.
+func (t *jsonTokeniser) afterValue() {
+	if t.indent != "" && len(t.stack) == 0 {
+		t.emit(Plain, "\n")
+	}
+}
+
+// newline starts a new, indented, line if pretty printing.
+func (t *jsonTokeniser) newline() {
+	if t.indent != "" {
+		t.emit(Plain, "\n"+strings.Repeat(t.indent, len(t.stack)))
+	}
+}
@@ -0,0 +1,228 @@
+package highlight
+
+import (
+	"strconv"
+	"strings"
+)
+
+// yamlTokeniser tokenises YAML a line at a time.
+//
+// It understands enough of YAML to highlight typical config files: block mappings and
+// sequences, flow collections, quoted and plain scalars, comments, block scalars, anchors,
+// aliases and tags. It does not handle quoted scalars that span lines.
+type yamlTokeniser struct {
+	emit        emitFunc
+	pending     string // Incomplete line
+	blockParent int    // Indent of the line that started a block scalar, if inBlock
+	inBlock     bool   // Whether the following more indented lines are a block scalar
+}
+
+func (t *yamlTokeniser) write(src string) {
+	t.pending += src
+
+	for {
+		i := strings.IndexByte(t.pending, '\n')
+		if i == -1 {
+			return
+		}
+
+		t.line(t.pending[:i])
+		t.emit(Plain, "\n")
+		t.pending = t.pending[i+1:]
+	}
+}
+
+func (t *yamlTokeniser) flush() {
+	if t.pending != "" {
+		t.line(t.pending)
+		t.pending = ""
+	}
+}
+
+// line tokenises a single line, without its trailing newline.
+func (t *yamlTokeniser) line(line string) {
+	rest := strings.TrimLeft(line, " ")
+	indent := len(line) - len(rest)
+
+	if t.inBlock {
+		if strings.TrimSpace(line) == "" || indent > t.blockParent {
+			t.emit(String, line)
+			return
+		}
+
+		t.inBlock = false
+	}
+
+	if indent > 0 {
+		t.emit(Plain, line[:indent])
+	}
+
+	if rest == "---" || rest == "..." || strings.HasPrefix(rest, "--- ") {
+		t.emit(Punctuation, rest[:3])
+		t.value(rest[3:], indent)
+
+		return
+	}
+
+	// Sequence entries, possibly nested e.g. "- - a"
+	for rest == "-" || strings.HasPrefix(rest, "- ") {
+		t.emit(Punctuation, "-")
+		spaces := len(rest[1:]) - len(strings.TrimLeft(rest[1:], " "))
+		t.emit(Plain, rest[1:1+spaces])
+		indent += 1 + spaces
+		rest = rest[1+spaces:]
+	}
+
+	if key := yamlKey(rest); key != "" {
+		t.emit(Key, key)
+		t.emit(Punctuation, ":")
+		rest = rest[len(key)+1:]
+	}
+
+	t.value(rest, indent)
+}
+
+// yamlKey returns the mapping key at the start of s, if there is one.
+func yamlKey(s string) string {
+	if s == "" || s[0] == '#' || s[0] == '{' || s[0] == '[' {
+		return ""
+	}
+
+	end := 0
+	if s[0] == '"' || s[0] == '\'' {
+		end = quotedLen(s)
+	}
+
+	for i := end; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i > 0 && s[i-1] == ' ':
+			return "" // A comment, no key
+		case s[i] == ':' && (i+1 == len(s) || s[i+1] == ' '):
+			return s[:i]
+		}
+	}
+
+	return ""
+}
+
+// value tokenises the value part of a line, everything after any key. indent is the
+// indent of the line's content, used to find the extent of a block scalar.
+func (t *yamlTokeniser) value(s string, indent int) {
+	depth := 0 // Depth of flow collections
+
+	for len(s) > 0 {
+		switch c := s[0]; {
+		case c == ' ' || c == '\t':
+			n := len(s) - len(strings.TrimLeft(s, " \t"))
+			t.emit(Plain, s[:n])
+			s = s[n:]
+		case c == '#':
+			t.emit(Comment, s)
+			return
+		case depth == 0 && (c == '|' || c == '>'):
+			// Block scalar indicator, possibly with chomping and indentation indicators e.g. "|-"
+			n := 1 + span(s[1:], func(c byte) bool { return c == '-' || c == '+' || ('0' <= c && c <= '9') })
+			t.emit(Punctuation, s[:n])
+			t.inBlock = true
+			t.blockParent = indent
+			s = s[n:]
+		case c == '"' || c == '\'':
+			n := quotedLen(s)
+			if next := strings.TrimLeft(s[n:], " "); depth > 0 && strings.HasPrefix(next, ":") {
+				t.emit(Key, s[:n])
+			} else {
+				t.emit(String, s[:n])
+			}
+
+			s = s[n:]
+		case strings.IndexByte("{}[],:", c) != -1:
+			switch c {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth = max(depth-1, 0)
+			}
+
+			t.emit(Punctuation, s[:1])
+			s = s[1:]
+		case c == '&' || c == '*' || c == '!':
+			n := span(s, func(c byte) bool { return c != ' ' && (depth == 0 || strings.IndexByte(",[]{}", c) == -1) })
+			t.emit(Keyword, s[:n])
+			s = s[n:]
+		default:
+			n := plainLen(s, depth > 0)
+			scalar := strings.TrimRight(s[:n], " ")
+
+			if next := strings.TrimLeft(s[len(scalar):], " "); depth > 0 && strings.HasPrefix(next, ":") {
+				t.emit(Key, scalar)
+			} else {
+				t.emit(scalarKind(scalar), scalar)
+			}
+
+			s = s[len(scalar):]
+		}
+	}
+}
+
+// plainLen returns the length of the plain scalar at the start of s, which in a flow
+// collection also ends at any flow indicator.
+func plainLen(s string, flow bool) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i > 0 && s[i-1] == ' ':
+			return i
+		case s[i] == ':' && (i+1 == len(s) || s[i+1] == ' '):
+			if flow {
+				return i
+			}
+		case flow && strings.IndexByte(",[]{}", s[i]) != -1:
+			return i
+		}
+	}
+
+	return len(s)
+}
+
+// quotedLen returns the length of the quoted scalar at the start of s, including its
+// quotes, or len(s) if it is not terminated.
+func quotedLen(s string) int {
+	quote := s[0]
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			if quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++ // '' is an escaped single quote
+				continue
+			}
+
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
+
+// scalarKind returns the kind of a plain scalar, following the YAML 1.2 core schema.
+func scalarKind(s string) Kind {
+	switch s {
+	case "true", "True", "TRUE", "false", "False", "FALSE":
+		return Bool
+	case "null", "Null", "NULL", "~":
+		return Null
+	case ".inf", ".Inf", ".INF", "+.inf", "-.inf", ".nan", ".NaN", ".NAN":
+		return Number
+	}
+
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return Number
+	}
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "_xXpP") {
+		return Number
+	}
+
+	return String
+}
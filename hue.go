@@ -39,6 +39,8 @@ func init() { //nolint: gochecknoinits // really the only option here
 	// Auto-determine whether or not colour should be enabled on package startup. FWIW I think
 	// init is kind of a smell but it is quite useful for this
 	enabled.Store(autoDetectEnabled())
+	level.Store(uint32(autoDetectLevel()))
+	simulation.Store(uint32(autoDetectSimulation()))
 }
 
 // Enabled sets whether the output from this package is colourised.
@@ -59,6 +61,14 @@ func Enabled(v bool) {
 	enabled.Store(v)
 }
 
+// IsEnabled reports whether the output from this package is currently colourised, either
+// from automatic detection or the last call to [Enabled].
+//
+// IsEnabled may be called safely from concurrently executing goroutines.
+func IsEnabled() bool {
+	return enabled.Load()
+}
+
 // Style is a terminal style to be applied to a piece of text, shown on a terminal.
 //
 // Styles are implemented in hue as bitflags so can be combined using the bitwise '|' operator,
@@ -323,27 +333,34 @@ func (s Style) Text(text string) string {
 // string for the result, even for composite styles: useful in hot paths where the caller
 // already maintains a []byte buffer.
 func (s Style) AppendText(dst, text []byte) []byte {
-	return appendStyled(s, dst, text)
+	return appendStyled(DefaultRenderer(), s, dst, text)
 }
 
 // AppendString is like [Style.AppendText] but takes the text as a string, avoiding the
 // []byte conversion (and its allocation) a caller would otherwise need to style a string.
 func (s Style) AppendString(dst []byte, text string) []byte {
-	return appendStyled(s, dst, text)
+	return appendStyled(DefaultRenderer(), s, dst, text)
 }
 
 // appendStyled is the shared, allocation-free implementation of [Style.AppendText] and
-// [Style.AppendString]. The text type set is constrained to the two types append accepts
-// after a []byte, so a string is appended without a []byte conversion.
-func appendStyled[T []byte | string](s Style, dst []byte, text T) []byte {
-	if !enabled.Load() {
+// [Style.AppendString] (and their [Renderer] equivalents), styling text with the settings of r.
+// The text type set is constrained to the two types append accepts after a []byte, so a string
+// is appended without a []byte conversion.
+func appendStyled[T []byte | string](r Renderer, s Style, dst []byte, text T) []byte {
+	if !r.Colour {
 		return append(dst, text...)
 	}
 
 	start := len(dst)
 	dst = append(dst, escape...)
 
-	dst, ok := s.appendCode(dst)
+	var ok bool
+	if r.Simulation != NoSimulation {
+		dst, ok = s.appendSimulatedCode(dst, r.Level, r.Simulation)
+	} else {
+		dst, ok = s.appendCode(dst)
+	}
+
 	if !ok {
 		// Invalid style: discard the escape we appended and fall back to raw text.
 		return append(dst[:start], text...)
@@ -396,16 +413,7 @@ func (s Style) appendCode(dst []byte) ([]byte, bool) {
 
 // wrap wraps text with the styles escape and reset sequences.
 func (s Style) wrap(text string) string {
-	if !enabled.Load() {
-		return text
-	}
-
-	code, err := s.Code()
-	if err != nil {
-		return text
-	}
-
-	return escape + code + "m" + text + reset
+	return DefaultRenderer().Text(s, text)
 }
 
 // autoDetectEnabled performs checks to auto detect whether or not this package should output
@@ -613,6 +613,20 @@ func TestStyleError(t *testing.T) {
 	}
 }
 
+func TestIsEnabled(t *testing.T) {
+	hue.Enabled(true)
+
+	if !hue.IsEnabled() {
+		t.Error("IsEnabled() = false after Enabled(true)")
+	}
+
+	hue.Enabled(false)
+
+	if hue.IsEnabled() {
+		t.Error("IsEnabled() = true after Enabled(false)")
+	}
+}
+
 func TestStyleCodeCombinations(t *testing.T) {
 	tests := []struct {
 		name  string    // Name of the test case
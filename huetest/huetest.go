@@ -0,0 +1,209 @@
+// Package huetest provides helpers for testing code that writes styled output with hue.
+//
+// Comparing raw escape sequences like "\x1b[1;4;32;44mhello\x1b[0m" makes for unreadable
+// tests and failure messages, so huetest renders them as tags instead:
+//
+//	huetest.Readable("\x1b[1;32mhello\x1b[0m") // "<bold,green>hello</>"
+//
+// [Equal] compares styled strings reporting any difference in this form, and [Golden] compares
+// against a golden file under testdata, updated by running the tests with the -update flag:
+//
+//	func TestHelp(t *testing.T) {
+//		huetest.Colour(t, true)
+//		huetest.Golden(t, cmd.Help())
+//	}
+//
+// The helpers that change whether hue is enabled do so globally, so they must not be used
+// in tests that call [testing.T.Parallel], render with a [hue.Renderer] in those instead.
+// Everything but hue's [hue.Spinner], [hue.ProgressBar], [hue.Live] and prompts can be
+// given one, see [hue.Renderer].
+package huetest // import "go.followtheprocess.codes/hue/huetest"
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/ansi"
+	"go.followtheprocess.codes/hue/diff"
+)
+
+// update is the -update flag, set to rewrite golden files with the output of the tests.
+var update = flag.Bool("update", false, "Update golden files with the output of the tests")
+
+// Colour sets whether hue is enabled for the duration of the test, restoring the previous
+// setting when the test and all its subtests complete.
+func Colour(tb testing.TB, enabled bool) {
+	tb.Helper()
+
+	previous := hue.IsEnabled()
+	hue.Enabled(enabled)
+
+	tb.Cleanup(func() { hue.Enabled(previous) })
+}
+
+// Equal reports a test failure if the styled strings got and want differ, showing both
+// in the form returned by [Readable].
+func Equal(tb testing.TB, got, want string) {
+	tb.Helper()
+
+	if got == want {
+		return
+	}
+
+	tb.Errorf("\nGot:\t%s\nWanted:\t%s\n", Readable(got), Readable(want))
+}
+
+// Golden compares got, in the form returned by [Readable], against the golden file for the
+// test: testdata/<test name>.golden. If they differ the test fails with a diff between them.
+//
+// When the tests are run with the -update flag the golden file is instead written (along with
+// any missing directories) with got, so the expected output can be reviewed in version control.
+func Golden(tb testing.TB, got string) {
+	tb.Helper()
+
+	path := filepath.Join("testdata", filepath.FromSlash(tb.Name())+".golden")
+	readable := Readable(got)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint: mnd,gosec // Usual permissions for a directory
+			tb.Fatalf("could not create directory for golden file: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(readable), 0o644); err != nil { //nolint: mnd,gosec // Usual permissions for a file
+			tb.Fatalf("could not update golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("could not read golden file (run with -update to create it): %v", err)
+	}
+
+	if string(want) == readable {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	r := diff.Renderer{
+		OldName:    path,
+		NewName:    "got",
+		Delete:     hue.Red,
+		Insert:     hue.Green,
+		DeleteWord: hue.Red | hue.Bold,
+		InsertWord: hue.Green | hue.Bold,
+		Header:     hue.Bold,
+	}
+	r.Unified(buf, string(want), readable) //nolint: errcheck // Writes to a bytes.Buffer cannot fail
+
+	tb.Errorf("output does not match golden file (run with -update to update it)\n%s", buf.String())
+}
+
+// Readable returns s with every ANSI escape sequence replaced by a readable tag.
+//
+// SGR sequences (styles) become a comma separated list of the attributes they set e.g.
+// "<bold,green>" and a reset becomes "</>". Colours are named for the basic 16 ("red",
+// "bright-blue"), by index for the 256 colour palette ("256:208") and by hex for truecolour
+// ("#ff8800"), with backgrounds given a "-bg" suffix ("red-bg", "#ff8800-bg") and underline
+// colours a "-ul" suffix. Attributes hue doesn't know of are shown by their SGR code.
+//
+// Any other escape sequences are shown by kind with their contents e.g. "<csi 2A>" to move
+// the cursor up 2 lines, "<osc 8;;https://go.dev>" for a hyperlink or "<esc 7>" to save the cursor.
+func Readable(s string) string {
+	b := &strings.Builder{}
+
+	for _, tok := range ansi.Parse(s) {
+		switch tok.Kind {
+		case ansi.Text, ansi.Control:
+			b.WriteString(tok.Raw)
+		case ansi.SGR:
+			b.WriteString(sgr(tok.Attrs))
+		case ansi.CSI:
+			b.WriteString("<csi " + tok.Raw[2:] + ">")
+		case ansi.OSC:
+			b.WriteString("<osc " + tok.Data + ">")
+		case ansi.DCS:
+			b.WriteString("<dcs " + strings.TrimSuffix(tok.Raw[2:], "\x1b\\") + ">")
+		default:
+			b.WriteString("<esc " + tok.Raw[1:] + ">")
+		}
+	}
+
+	return b.String()
+}
+
+// basic is the names of the basic 8 colours, in order of their SGR codes.
+var basic = [...]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// attributes is the names of the SGR attributes other than colours.
+var attributes = map[ansi.AttrKind]string{
+	ansi.Reset:                  "reset",
+	ansi.Bold:                   "bold",
+	ansi.Dim:                    "dim",
+	ansi.Italic:                 "italic",
+	ansi.Underline:              "underline",
+	ansi.Blink:                  "blink",
+	ansi.Reverse:                "reverse",
+	ansi.Hidden:                 "hidden",
+	ansi.Strikethrough:          "strikethrough",
+	ansi.NormalIntensity:        "no-bold",
+	ansi.NotItalic:              "no-italic",
+	ansi.NotUnderline:           "no-underline",
+	ansi.NotBlink:               "no-blink",
+	ansi.NotReverse:             "no-reverse",
+	ansi.NotHidden:              "no-hidden",
+	ansi.NotStrikethrough:       "no-strikethrough",
+	ansi.DefaultForeground:      "default",
+	ansi.DefaultBackground:      "default-bg",
+	ansi.DefaultUnderlineColour: "default-ul",
+}
+
+// sgr returns the readable tag for the attributes of an SGR sequence.
+func sgr(attrs []ansi.Attr) string {
+	if len(attrs) == 1 && attrs[0].Kind == ansi.Reset {
+		return "</>"
+	}
+
+	names := make([]string, 0, len(attrs))
+
+	for _, attr := range attrs {
+		switch attr.Kind {
+		case ansi.Foreground:
+			names = append(names, colour(attr.Colour))
+		case ansi.Background:
+			names = append(names, colour(attr.Colour)+"-bg")
+		case ansi.UnderlineColour:
+			names = append(names, colour(attr.Colour)+"-ul")
+		case ansi.Unknown:
+			names = append(names, strconv.Itoa(attr.Code))
+		default:
+			names = append(names, attributes[attr.Kind])
+		}
+	}
+
+	return "<" + strings.Join(names, ",") + ">"
+}
+
+// colour returns the readable name of c.
+func colour(c ansi.Colour) string {
+	switch c.Type {
+	case ansi.Indexed:
+		return "256:" + strconv.Itoa(int(c.Index))
+	case ansi.TrueColour:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	default:
+		if c.Index >= 8 { //nolint: mnd // The bright colours follow the basic 8
+			return "bright-" + basic[c.Index-8]
+		}
+
+		return basic[c.Index]
+	}
+}
@@ -0,0 +1,124 @@
+package huetest_test
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/huetest"
+)
+
+// recorder is a [testing.TB] that records failures rather than failing the test.
+type recorder struct {
+	testing.TB
+	name   string
+	errors []string
+}
+
+func (r *recorder) Name() string {
+	return r.name
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestReadable(t *testing.T) {
+	tests := []struct {
+		name  string // Name of the test case
+		input string // Styled input
+		want  string // Expected readable output
+	}{
+		{name: "plain", input: "hello", want: "hello"},
+		{name: "style", input: "\x1b[1;4;32;44mhello\x1b[0m", want: "<bold,underline,green,blue-bg>hello</>"},
+		{name: "bright", input: "\x1b[92;100mhi\x1b[m", want: "<bright-green,bright-black-bg>hi</>"},
+		{name: "256", input: "\x1b[38;5;208;48;5;16mhi\x1b[0m", want: "<256:208,256:16-bg>hi</>"},
+		{name: "truecolour", input: "\x1b[38;2;255;136;0;48;2;0;0;10mhi\x1b[0m", want: "<#ff8800,#00000a-bg>hi</>"},
+		{name: "reset and style", input: "\x1b[0;3mhi", want: "<reset,italic>hi"},
+		{name: "underline colour", input: "\x1b[4;58:2::255:0:0mhi\x1b[59m", want: "<underline,#ff0000-ul>hi<default-ul>"},
+		{name: "unknown", input: "\x1b[53mhi", want: "<53>hi"},
+		{name: "control", input: "\x1b[2A\x1b[2K\rhi", want: "<csi 2A><csi 2K>\rhi"},
+		{name: "hyperlink", input: "\x1b]8;;https://go.dev\x1b\\Go\x1b]8;;\a", want: "<osc 8;;https://go.dev>Go<osc 8;;>"},
+		{name: "two byte", input: "\x1b7hi\x1b8", want: "<esc 7>hi<esc 8>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strconv.Quote(huetest.Readable(tt.input))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestColour(t *testing.T) {
+	hue.Enabled(false)
+
+	t.Run("enabled", func(t *testing.T) {
+		huetest.Colour(t, true)
+
+		if !hue.IsEnabled() {
+			t.Error("Colour(t, true) did not enable hue")
+		}
+	})
+
+	if hue.IsEnabled() {
+		t.Error("Colour did not restore the previous setting")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	hue.Enabled(true)
+
+	rec := &recorder{TB: t}
+	huetest.Equal(rec, hue.Green.Text("ok"), hue.Green.Text("ok"))
+
+	if len(rec.errors) != 0 {
+		t.Fatalf("Equal failed for equal strings: %v", rec.errors)
+	}
+
+	huetest.Equal(rec, hue.Red.Text("ok"), hue.Green.Text("ok"))
+
+	if len(rec.errors) != 1 {
+		t.Fatalf("Equal did not fail for different strings")
+	}
+
+	want := "\nGot:\t<red>ok</>\nWanted:\t<green>ok</>\n"
+	if rec.errors[0] != want {
+		t.Errorf("\nGot:\t%q\nWanted:\t%q\n", rec.errors[0], want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	huetest.Colour(t, true)
+
+	output := hue.Bold.Text("Usage:") + " hue [flags]\n\n" + hue.Green.Text("OK") + "\n"
+	huetest.Golden(t, output)
+
+	t.Run("mismatch", func(t *testing.T) {
+		if flag.Lookup("update").Value.String() == "true" {
+			t.Skip("golden files are being updated")
+		}
+
+		huetest.Colour(t, false)
+
+		rec := &recorder{TB: t, name: "TestGolden"}
+		huetest.Golden(rec, hue.Bold.Text("Usage:")+" hue [flags]\n\nFAILED\n")
+
+		if len(rec.errors) != 1 {
+			t.Fatalf("Golden did not fail for different output")
+		}
+
+		for _, line := range []string{"-<green>OK</>", "+FAILED"} {
+			if !strings.Contains(rec.errors[0], line) {
+				t.Errorf("diff %q does not contain %q", rec.errors[0], line)
+			}
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package hue
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Live is a block of lines at the bottom of the output that is redrawn in place, for showing the
+// status of many concurrent tasks at once, while log lines written with [Live.Println] or
+// [Live.Write] scroll above it:
+//
+//	live := &hue.Live{}
+//	live.Start()
+//	defer live.Stop()
+//
+//	for _, image := range images {
+//		line := live.Add(image + ": waiting")
+//		go func() {
+//			pull(image)
+//			line.Update(image + ": " + hue.Green.Text("done"))
+//			live.Println("pulled", image)
+//		}()
+//	}
+//
+// Lines are redrawn every Interval, but only if something has changed. Each line must fit within
+// the width of the terminal and not contain newlines, or the region can't be redrawn correctly.
+//
+// When hue is disabled (see [Enabled]) nothing is redrawn: log lines are written as normal and the
+// final state of every line is written once by [Live.Stop].
+//
+// The zero value is ready to use and draws on [os.Stderr]. The exported fields must not be changed
+// after calling [Live.Start], but all methods, and those of every [LiveLine], may be called safely
+// from concurrently executing goroutines. Errors writing to Out are ignored.
+type Live struct {
+	Out      io.Writer     // Where to draw the region, defaults to os.Stderr
+	Interval time.Duration // Time between redraws, defaults to 100ms
+	lines    []*LiveLine   // Lines in the region, in order
+	anim     animation     // Manages the render loop
+	drawn    int           // Number of lines drawn by the last render
+	dirty    bool          // Whether the region has changed since the last render
+}
+
+// LiveLine is a single line in a [Live] region.
+type LiveLine struct {
+	live *Live  // The region the line belongs to
+	text string // Current text of the line
+}
+
+// Start starts drawing the region.
+//
+// Start is a no-op if the region is already running.
+func (l *Live) Start() {
+	l.anim.lifecycle.Lock()
+	defer l.anim.lifecycle.Unlock()
+
+	l.anim.mu.Lock()
+	defer l.anim.mu.Unlock()
+
+	// Defaulted under the lock, as Write and Println read it
+	if l.Out == nil {
+		l.Out = os.Stderr
+	}
+
+	if l.anim.running {
+		return
+	}
+
+	l.drawn = 0
+	l.anim.start(l.Out, l.Interval, defaultInterval, l.render)
+
+	if l.anim.live {
+		l.draw("")
+	}
+}
+
+// Add appends a new line showing text to the bottom of the region, returning it so it can
+// be updated.
+func (l *Live) Add(text string) *LiveLine {
+	line := &LiveLine{live: l, text: text}
+
+	l.anim.mu.Lock()
+	defer l.anim.mu.Unlock()
+
+	l.lines = append(l.lines, line)
+	l.dirty = true
+
+	return line
+}
+
+// Update replaces the text of the line.
+func (line *LiveLine) Update(text string) {
+	line.live.anim.mu.Lock()
+	defer line.live.anim.mu.Unlock()
+
+	if text != line.text {
+		line.text = text
+		line.live.dirty = true
+	}
+}
+
+// Remove removes the line from its region, any further updates to it are ignored.
+func (line *LiveLine) Remove() {
+	l := line.live
+
+	l.anim.mu.Lock()
+	defer l.anim.mu.Unlock()
+
+	for i, other := range l.lines {
+		if other == line {
+			l.lines = append(l.lines[:i], l.lines[i+1:]...)
+			l.dirty = true
+
+			return
+		}
+	}
+}
+
+// Write implements [io.Writer] for a [Live] region, writing p above the region as one or
+// more log lines. A trailing newline is added if p doesn't end with one.
+//
+// This allows a Live region to be used as the output of a logger while it is running.
+//
+// It returns len(p) and a nil error.
+func (l *Live) Write(p []byte) (n int, err error) {
+	text := string(p)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	l.anim.mu.Lock()
+	defer l.anim.mu.Unlock()
+
+	if l.anim.live && l.anim.running {
+		l.draw(text)
+	} else {
+		out := l.Out
+		if out == nil {
+			out = os.Stderr
+		}
+
+		io.WriteString(out, text) //nolint: errcheck // Documented that write errors are ignored
+	}
+
+	return len(p), nil
+}
+
+// Println formats its arguments as [fmt.Println] and writes them above the region.
+func (l *Live) Println(a ...any) {
+	l.Write([]byte(fmt.Sprintln(a...))) //nolint: errcheck // Write never returns an error
+}
+
+// Printf formats according to a format specifier and writes the result above the region.
+func (l *Live) Printf(format string, a ...any) {
+	l.Write(fmt.Appendf(nil, format, a...)) //nolint: errcheck // Write never returns an error
+}
+
+// Stop stops the region, drawing it one final time and leaving it in the output.
+//
+// Stop is a no-op if the region is not running.
+func (l *Live) Stop() {
+	l.anim.stop(func() {
+		if l.anim.live {
+			l.draw("")
+			return
+		}
+
+		for _, line := range l.lines {
+			l.anim.write(line.text + "\n")
+		}
+	})
+}
+
+// render is called by the render loop to redraw the region, l.anim.mu is held.
+func (l *Live) render() {
+	if l.dirty && l.anim.live {
+		l.draw("")
+	}
+}
+
+// draw redraws the region in place, first writing log (which must be empty or end in
+// a newline) over the top of it so that it scrolls up above the region.
+//
+// l.anim.mu must be held.
+func (l *Live) draw(log string) {
+	var buf []byte
+
+	// Move back to the first line of the region
+	buf = append(buf, '\r')
+	if l.drawn > 0 {
+		buf = append(buf, escape...)
+		buf = strconv.AppendInt(buf, int64(l.drawn), 10)
+		buf = append(buf, 'A')
+	}
+
+	// Overwrite each line, rather than clearing the whole region first, to avoid flicker
+	for logLine := range strings.Lines(log) {
+		buf = append(buf, strings.TrimSuffix(logLine, "\n")...)
+		buf = append(buf, eraseLine+"\n"...)
+	}
+
+	for _, line := range l.lines {
+		buf = append(buf, line.text...)
+		buf = append(buf, eraseLine+"\n"...)
+	}
+
+	// Clear anything left over from a previous, longer, render
+	buf = append(buf, eraseDown...)
+
+	l.anim.write(string(buf))
+	l.drawn = len(l.lines)
+	l.dirty = false
+}
@@ -0,0 +1,115 @@
+package hue_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestLive(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	live := &hue.Live{Out: out, Interval: time.Hour}
+
+	live.Start()
+	one := live.Add("one: waiting")
+	two := live.Add("two: waiting")
+	live.Println("starting")
+	one.Update(hue.Green.Text("one: done"))
+	two.Remove()
+	live.Stop()
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote(
+		"\x1b[?25l" +
+			"\r\x1b[J" + // Initial empty region
+			"\rstarting\x1b[K\none: waiting\x1b[K\ntwo: waiting\x1b[K\n\x1b[J" + // Log line scrolls above the region
+			"\r\x1b[2A\x1b[32mone: done\x1b[0m\x1b[K\n\x1b[J" + // Final render, two removed
+			"\x1b[?25h",
+	)
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestLiveNotLive(t *testing.T) {
+	hue.Enabled(false)
+
+	out := &syncBuffer{}
+	live := &hue.Live{Out: out, Interval: time.Hour}
+
+	live.Start()
+	one := live.Add("one: waiting")
+	live.Add("two: waiting")
+	live.Printf("pulled %s", "one")
+	one.Update("one: done")
+	live.Stop()
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote("pulled one\none: done\ntwo: waiting\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestLiveConcurrent(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	live := &hue.Live{Out: out, Interval: time.Millisecond}
+	live.Start()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		line := live.Add("job " + strconv.Itoa(i))
+
+		wg.Go(func() {
+			for j := range 100 {
+				line.Update("job " + strconv.Itoa(i) + ": " + strconv.Itoa(j))
+			}
+
+			live.Println("finished job", i)
+		})
+	}
+
+	wg.Wait()
+	live.Stop()
+}
+
+func TestLiveConcurrentStart(t *testing.T) {
+	hue.Enabled(false) // Nothing is written to the default os.Stderr without any lines
+
+	live := &hue.Live{Interval: time.Millisecond}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			live.Start()
+			live.Stop()
+		})
+	}
+
+	wg.Wait()
+
+	out := &syncBuffer{}
+	live = &hue.Live{Out: out, Interval: time.Millisecond}
+
+	hue.Enabled(true)
+
+	for range 50 {
+		wg.Go(func() {
+			live.Start()
+			live.Stop()
+		})
+	}
+
+	wg.Wait()
+
+	checkRuns(t, out.String())
+}
@@ -0,0 +1,375 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// maxIndent is the most a block may be indented by and still be recognised, CommonMark
+// treats anything more as an indented code block (which we don't support, so it's a paragraph).
+const maxIndent = 3
+
+// indentation returns the number of leading spaces in line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// fence returns the opening code fence at the start of line (already trimmed) e.g. "```",
+// or "" if it doesn't start with one.
+func fence(line string) string {
+	for _, char := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, char))
+		if n < 3 { //nolint: mnd // A fence is at least 3 characters
+			continue
+		}
+
+		// The info string of a backtick fence can't contain backticks, or it would be inline code
+		if char == "`" && strings.Contains(line[n:], "`") {
+			return ""
+		}
+
+		return line[:n]
+	}
+
+	return ""
+}
+
+// fencedCode returns the lines of the fenced code block starting at lines[start] and the
+// index of the line after it. A code block without a closing fence runs to the end of
+// the document.
+func fencedCode(lines []string, start int) (code []string, next int) {
+	open := strings.TrimSpace(lines[start])
+	open = fence(open)
+	indent := indentation(lines[start])
+
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if closing := fence(trimmed); closing != "" && closing[0] == open[0] && len(closing) >= len(open) &&
+			trimmed == closing {
+			return code, i + 1
+		}
+
+		// Content is un-indented by the indentation of the opening fence
+		line := lines[i]
+		line = line[min(indent, indentation(line)):]
+		code = append(code, line)
+	}
+
+	return code, len(lines)
+}
+
+// isRule reports whether line (already trimmed) is a thematic break e.g. "---" or "* * *".
+func isRule(line string) bool {
+	if line == "" || !strings.Contains("-*_", line[:1]) {
+		return false
+	}
+
+	count := 0
+
+	for _, char := range line {
+		switch char {
+		case rune(line[0]):
+			count++
+		case ' ':
+		default:
+			return false
+		}
+	}
+
+	return count >= 3 //nolint: mnd // A rule is at least 3 characters
+}
+
+// headingLevel returns the level of the ATX heading in line (already trimmed) e.g. 2 for
+// "## Usage", or 0 if it isn't a heading.
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 {
+		return 0
+	}
+
+	if level < len(line) && line[level] != ' ' {
+		return 0 // e.g. "#hashtag"
+	}
+
+	return level
+}
+
+// blockQuote returns the contents of the block quote starting at lines[start], with the
+// leading '>' removed, and the index of the line after it.
+//
+// As in CommonMark, a paragraph in a block quote may continue on lines without the '>'.
+func blockQuote(lines []string, start int) (quote []string, next int) {
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			break
+		}
+
+		if !strings.HasPrefix(trimmed, ">") {
+			// Lazy continuation of a paragraph
+			if len(quote) == 0 || strings.TrimSpace(quote[len(quote)-1]) == "" || startsBlock(lines, i) {
+				break
+			}
+
+			quote = append(quote, trimmed)
+
+			continue
+		}
+
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		trimmed = strings.TrimPrefix(trimmed, " ")
+		quote = append(quote, trimmed)
+	}
+
+	return quote, i
+}
+
+// marker is the marker at the start of a list item.
+type marker struct {
+	number    string // The number of an ordered list item
+	delimiter string // The delimiter after the number of an ordered item ('.' or ')'), or the bullet character
+	width     int    // Width of the indentation, marker and following spaces i.e. where the content starts
+	ordered   bool   // Whether it is an ordered (numbered) list item
+	ok        bool   // Whether the line starts with a list item at all
+}
+
+// listMarker parses the list item marker at the start of line, if there is one.
+func listMarker(line string) marker {
+	indent := indentation(line)
+	if indent > maxIndent {
+		return marker{}
+	}
+
+	rest := line[indent:]
+
+	var m marker
+
+	switch {
+	case rest == "":
+		return marker{}
+	case strings.Contains("-*+", rest[:1]):
+		m.delimiter = rest[:1]
+		rest = rest[1:]
+	default:
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 || digits > 9 || digits == len(rest) || !strings.Contains(".)", rest[digits:digits+1]) {
+			return marker{}
+		}
+
+		m.ordered = true
+		m.number = rest[:digits]
+		m.delimiter = rest[digits : digits+1]
+		rest = rest[digits+1:]
+	}
+
+	// The marker must be followed by a space, or be the whole line
+	spaces := indentation(rest)
+	if spaces == 0 && rest != "" {
+		return marker{}
+	}
+
+	if spaces > 4 || rest == "" { //nolint: mnd // CommonMark: more than 4 spaces means indented code in the item
+		spaces = 1
+	}
+
+	m.width = len(line) - len(rest) + spaces
+	m.ok = true
+
+	return m
+}
+
+// listItem is a single item of a list.
+type listItem struct {
+	marker marker   // The item's marker
+	lines  []string // The contents of the item, un-indented
+	loose  bool     // Whether the item is separated from the next by a blank line
+}
+
+// list returns the items of the list starting at lines[start] and the index of the
+// line after it.
+//
+// A list continues for as long as there are items of the same type (bullet or the same
+// ordered delimiter). Lines indented at least as far as an item's content belong to that item,
+// which is how nested lists and multiple paragraphs in an item are written.
+func list(lines []string, start int) (items []listItem, next int) {
+	first := listMarker(lines[start])
+
+	i := start
+	for i < len(lines) {
+		m := listMarker(lines[i])
+		if !m.ok || m.ordered != first.ordered || m.delimiter != first.delimiter || isRule(strings.TrimSpace(lines[i])) {
+			break
+		}
+
+		item := listItem{marker: m, lines: []string{lines[i][min(m.width, len(lines[i])):]}}
+
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			trimmed := strings.TrimSpace(line)
+
+			if trimmed == "" {
+				// A blank line ends the item unless the next non blank line is indented into it
+				j := i
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+
+				if j < len(lines) && indentation(lines[j]) >= m.width {
+					item.lines = append(item.lines, "")
+					i = j
+
+					continue
+				}
+
+				if j < len(lines) {
+					if next := listMarker(lines[j]); next.ok && next.ordered == first.ordered && next.delimiter == first.delimiter {
+						item.loose = true
+					}
+				}
+
+				i = j
+
+				break
+			}
+
+			if indentation(line) >= m.width {
+				item.lines = append(item.lines, line[m.width:])
+				i++
+
+				continue
+			}
+
+			// Lazy continuation of the item's paragraph
+			last := item.lines[len(item.lines)-1]
+			if strings.TrimSpace(last) != "" && !startsBlock(lines, i) && !listMarker(line).ok {
+				item.lines = append(item.lines, trimmed)
+				i++
+
+				continue
+			}
+
+			break
+		}
+
+		items = append(items, item)
+
+		if i > 0 && strings.TrimSpace(lines[i-1]) == "" && !item.loose {
+			// The blank line ended the list
+			break
+		}
+	}
+
+	return items, i
+}
+
+// isTable reports whether a table starts at lines[start], that is the line contains a '|'
+// and is followed by a delimiter row e.g. "|---|:---:|".
+func isTable(lines []string, start int) bool {
+	if start+1 >= len(lines) || !strings.Contains(lines[start], "|") {
+		return false
+	}
+
+	delimiters := tableCells(lines[start+1])
+	if len(delimiters) == 0 || len(delimiters) != len(tableCells(lines[start])) {
+		return false
+	}
+
+	for _, cell := range delimiters {
+		cell = strings.TrimPrefix(cell, ":")
+		cell = strings.TrimSuffix(cell, ":")
+
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// table returns the rows of the table starting at lines[start] and the index of
+// the line after it.
+func table(lines []string, start int) (rows []string, next int) {
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || (i > start+1 && startsBlock(lines, i)) {
+			break
+		}
+
+		rows = append(rows, trimmed)
+	}
+
+	return rows, i
+}
+
+// tableCells splits a table row into its (trimmed) cells. Leading and trailing pipes
+// are optional and a pipe may be escaped with a backslash to include it in a cell.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = strings.TrimSuffix(row, "|")
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// paragraphLines returns the (trimmed) lines of the paragraph starting at lines[start]
+// and the index of the line after it.
+func paragraphLines(lines []string, start int) (paragraph []string, next int) {
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || (i > start && startsBlock(lines, i)) {
+			break
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+
+	return paragraph, i
+}
+
+// startsBlock reports whether lines[i] starts a new block, interrupting a paragraph.
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	trimmed := strings.TrimSpace(line)
+
+	if indentation(line) > maxIndent {
+		return false
+	}
+
+	switch {
+	case fence(trimmed) != "", isRule(trimmed), headingLevel(trimmed) > 0, strings.HasPrefix(trimmed, ">"):
+		return true
+	case isTable(lines, i):
+		return true
+	}
+
+	// As in CommonMark, only ordered lists starting at 1 may interrupt a paragraph, so
+	// a sentence that happens to start with a number isn't a list
+	m := listMarker(line)
+
+	return m.ok && strings.TrimSpace(line[min(m.width, len(line)):]) != "" && (!m.ordered || m.number == "1")
+}
@@ -0,0 +1,276 @@
+package markdown
+
+import (
+	"strings"
+
+	"go.followtheprocess.codes/hue"
+)
+
+// segment is a run of inline text in a single style.
+type segment struct {
+	text  string    // The text, with any Markdown syntax removed
+	url   string    // The destination of the link the text is part of, if any
+	style hue.Style // Style of the text, the combination of every element it's nested in
+}
+
+// inline renders a line of inline Markdown (emphasis, code spans, links etc.) with
+// base as the style of any plain text.
+func (r Renderer) inline(text string, base hue.Style) string {
+	var segments []segment
+
+	r.parseInline(text, base, "", &segments)
+
+	s := &strings.Builder{}
+
+	for i := 0; i < len(segments); {
+		if segments[i].url == "" {
+			s.WriteString(r.Renderer.Text(segments[i].style, segments[i].text))
+			i++
+
+			continue
+		}
+
+		// Every consecutive segment of the same link is made into a single hyperlink
+		url := segments[i].url
+		link := &strings.Builder{}
+
+		for ; i < len(segments) && segments[i].url == url; i++ {
+			link.WriteString(r.Renderer.Text(segments[i].style, segments[i].text))
+		}
+
+		s.WriteString(r.Renderer.Hyperlink(url, link.String()))
+	}
+
+	return s.String()
+}
+
+// parseInline parses text into segments, appending them to segments.
+//
+// Emphasis and links may be nested, style is the combination of the styles of
+// every element text is nested in and url the destination of the link it's in (if any).
+func (r Renderer) parseInline(text string, style hue.Style, url string, segments *[]segment) {
+	plain := &strings.Builder{}
+
+	// emit appends a segment, first flushing any plain text before it
+	emit := func(seg segment) {
+		if plain.Len() > 0 {
+			*segments = append(*segments, segment{text: plain.String(), style: style, url: url})
+			plain.Reset()
+		}
+
+		if seg.text != "" {
+			*segments = append(*segments, seg)
+		}
+	}
+
+	for i := 0; i < len(text); {
+		char := text[i]
+
+		switch {
+		case char == '\\' && i+1 < len(text) && isPunct(text[i+1]):
+			// Backslash escape
+			plain.WriteByte(text[i+1])
+			i += 2
+
+			continue
+		case char == '`':
+			// Code span, closed by a run of backticks of the same length
+			n := run(text[i:], '`')
+			delim := text[i : i+n]
+
+			if end := closingRun(text[i+n:], delim); end >= 0 {
+				code := text[i+n : i+n+end]
+				// As in CommonMark, a single leading and trailing space is stripped so code
+				// spans can contain backticks e.g. `` `hue` ``
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+					code = code[1 : len(code)-1]
+				}
+
+				emit(segment{text: code, style: style | r.Theme.Code, url: url})
+				i += n + end + n
+
+				continue
+			}
+
+			// No closing run, the backticks are literal
+			plain.WriteString(delim)
+			i += n
+
+			continue
+		case char == '*' || char == '_':
+			n := min(run(text[i:], char), 2) //nolint: mnd // We support *emphasis* and **strong**, not ***both***
+			delim := text[i : i+n]
+
+			// An underscore inside a word (e.g. snake_case) isn't emphasis
+			opens := i+n < len(text) && text[i+n] != ' ' && (char == '*' || i == 0 || !isWordChar(text[i-1]))
+
+			if end := closingDelim(text[i+n:], delim); opens && end > 0 {
+				emphasis := r.Theme.Emphasis
+				if n == 2 { //nolint: mnd // ** is strong
+					emphasis = r.Theme.Strong
+				}
+
+				emit(segment{})
+				r.parseInline(text[i+n:i+n+end], style|emphasis, url, segments)
+				i += n + end + n
+
+				continue
+			}
+
+			plain.WriteString(delim)
+			i += n
+
+			continue
+		case char == '[' || (char == '!' && i+1 < len(text) && text[i+1] == '['):
+			// Link, or image which we show as a link to the image with its alt text
+			start := i
+			if char == '!' {
+				start++
+			}
+
+			label, dest, n := parseLink(text[start:])
+			if n > 0 {
+				if label == "" {
+					label = dest
+				}
+
+				emit(segment{})
+				r.parseInline(label, style|r.Theme.Link, dest, segments)
+				i = start + n
+
+				continue
+			}
+		case char == '<':
+			// Autolink e.g. <https://go.dev>
+			if end := strings.IndexByte(text[i:], '>'); end > 0 {
+				dest := text[i+1 : i+end]
+				if !strings.ContainsAny(dest, " <") && (strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:")) {
+					emit(segment{text: dest, style: style | r.Theme.Link, url: dest})
+					i += end + 1
+
+					continue
+				}
+			}
+		}
+
+		plain.WriteByte(char)
+		i++
+	}
+
+	emit(segment{})
+}
+
+// parseLink parses a link of the form [label](destination "title") at the start of text,
+// returning the label, destination and the length of the link in bytes, or 0 if text
+// doesn't start with a link.
+func parseLink(text string) (label, dest string, n int) {
+	// Find the matching ']', labels may contain brackets as long as they're balanced
+	depth := 0
+	end := -1
+
+	for i := 0; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+
+	if end < 0 || end+1 >= len(text) || text[end+1] != '(' {
+		return "", "", 0
+	}
+
+	closing := strings.IndexByte(text[end+1:], ')')
+	if closing < 0 {
+		return "", "", 0
+	}
+
+	dest = strings.TrimSpace(text[end+2 : end+1+closing])
+	// Drop an optional title e.g. (https://go.dev "Go")
+	if space := strings.IndexByte(dest, ' '); space >= 0 {
+		dest = dest[:space]
+	}
+
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+
+	return text[1:end], dest, end + 1 + closing + 1
+}
+
+// run returns the number of consecutive occurrences of char at the start of text.
+func run(text string, char byte) int {
+	n := 0
+	for n < len(text) && text[n] == char {
+		n++
+	}
+
+	return n
+}
+
+// closingRun returns the index in text of a run of backticks exactly the same as delim,
+// or -1 if there isn't one.
+func closingRun(text, delim string) int {
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+
+		n := run(text[i:], '`')
+		if n == len(delim) {
+			return i
+		}
+
+		i += n
+	}
+
+	return -1
+}
+
+// closingDelim returns the index in text of the emphasis delimiter closing delim, or -1
+// if there isn't one. The closing delimiter must not follow a space and, for underscores,
+// must not be followed by a word character.
+func closingDelim(text, delim string) int {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == '`':
+			// Delimiters inside code spans don't count
+			n := run(text[i:], '`')
+			if end := closingRun(text[i+n:], text[i:i+n]); end >= 0 {
+				i += n + end + n - 1
+			}
+		case strings.HasPrefix(text[i:], delim) && i > 0 && text[i-1] != ' ':
+			after := i + len(delim)
+			if len(delim) == 1 && after < len(text) && text[after] == delim[0] {
+				// Part of a ** run, e.g. *emphasis with **strong** inside*
+				i = after + run(text[after:], delim[0]) - 1
+				continue
+			}
+
+			if delim[0] == '_' && after < len(text) && isWordChar(text[after]) {
+				continue
+			}
+
+			return i
+		}
+	}
+
+	return -1
+}
+
+// isPunct reports whether c is ASCII punctuation, which may be backslash escaped.
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// isWordChar reports whether c is an ASCII letter, digit or underscore.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,380 @@
+// Package markdown renders Markdown as styled text for the terminal, for showing help text,
+// changelogs or release notes in a CLI.
+//
+// It supports a subset of CommonMark: headings, emphasis, inline code, fenced code blocks,
+// lists, block quotes, links, thematic breaks and (GitHub flavoured) tables:
+//
+//	r := markdown.Renderer{Theme: markdown.DefaultTheme}
+//	r.Render(os.Stdout, changelog)
+//
+// Paragraphs are wrapped to the width of the terminal with a [hue.Wrapper], tables are aligned
+// with [tabwriter] and links are rendered with [hue.Hyperlink] so they are clickable in terminals
+// that support it. Fenced code blocks in JSON, YAML or Go are syntax highlighted by [highlight].
+//
+// Control characters in the source are removed, so that an untrusted document can't write
+// escape sequences of its own e.g. to end a link early.
+//
+// Like the rest of hue, the renderer is forgiving: anything it doesn't understand (e.g. raw HTML)
+// is shown as plain text rather than failing.
+package markdown // import "go.followtheprocess.codes/hue/markdown"
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/highlight"
+	"go.followtheprocess.codes/hue/tabwriter"
+	"golang.org/x/term"
+)
+
+// defaultWidth is the width to wrap to if it isn't set and can't be detected from the terminal.
+const defaultWidth = 80
+
+// Theme is the set of styles to render Markdown with.
+type Theme struct {
+	Syntax      highlight.Theme // Styles for fenced code blocks in JSON, YAML or Go
+	Heading1    hue.Style       // Style for top level (#) headings
+	Heading     hue.Style       // Style for all other headings
+	Emphasis    hue.Style       // Style for *emphasis*
+	Strong      hue.Style       // Style for **strong** emphasis
+	Code        hue.Style       // Style for `inline code`
+	CodeBlock   hue.Style       // Style for code blocks in any other language
+	Link        hue.Style       // Style for the text of links
+	Quote       hue.Style       // Style for the bar down the side of block quotes
+	ListMarker  hue.Style       // Style for bullets and numbers of list items
+	Rule        hue.Style       // Style for thematic breaks (---)
+	TableHeader hue.Style       // Style for the header row of tables
+}
+
+// DefaultTheme is a [Theme] using the basic colours, so it works on any terminal.
+var DefaultTheme = Theme{
+	Heading1:    hue.Magenta | hue.Bold | hue.Underline,
+	Heading:     hue.Magenta | hue.Bold,
+	Emphasis:    hue.Italic,
+	Strong:      hue.Bold,
+	Code:        hue.Cyan,
+	CodeBlock:   hue.Cyan,
+	Link:        hue.Blue | hue.Underline,
+	Quote:       hue.BrightBlack,
+	ListMarker:  hue.BrightBlack,
+	Rule:        hue.BrightBlack,
+	TableHeader: hue.Bold,
+	Syntax:      highlight.DefaultTheme,
+}
+
+// Renderer renders Markdown. The zero value renders without any styles, wrapped to the
+// width of the terminal.
+type Renderer struct {
+	Renderer *hue.Renderer // Renders the Theme's styles and links, nil uses the package wide settings
+	Theme    Theme         // Styles for each Markdown element
+	Width    int           // Width to wrap text to, 0 means the width of the terminal (or 80 if it can't be detected), < 0 disables wrapping
+}
+
+// Text returns the Markdown src rendered for the terminal.
+func (r Renderer) Text(src string) string {
+	r.Renderer = r.renderer()
+
+	lines := r.blocks(splitLines(src), r.width(), false)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Render renders the Markdown src to w.
+func (r Renderer) Render(w io.Writer, src string) error {
+	_, err := io.WriteString(w, r.Text(src))
+	return err
+}
+
+// width returns the width to wrap text to, <= 0 means no wrapping.
+func (r Renderer) width() int {
+	if r.Width != 0 {
+		return r.Width
+	}
+
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+
+	return defaultWidth
+}
+
+// renderer returns the hue Renderer to render styles and links with.
+func (r Renderer) renderer() *hue.Renderer {
+	if r.Renderer != nil {
+		return r.Renderer
+	}
+
+	renderer := hue.DefaultRenderer()
+
+	return &renderer
+}
+
+// blocks renders lines of Markdown as a sequence of blocks separated by blank lines,
+// returning the rendered lines. If tight is true (for the items of a tight list)
+// blocks aren't separated.
+//
+// It is called recursively for the contents of list items and block quotes, with
+// width reduced by the width of their markers.
+func (r Renderer) blocks(lines []string, width int, tight bool) []string {
+	var out []string
+
+	// add appends a rendered block to out, separating it from the last
+	add := func(block []string) {
+		if len(out) > 0 && !tight {
+			out = append(out, "")
+		}
+
+		out = append(out, block...)
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+		case fence(trimmed) != "":
+			var code []string
+
+			code, i = fencedCode(lines, i)
+			// The language is the first word of the info string after the fence e.g. ```go
+			lang, _, _ := strings.Cut(strings.TrimSpace(strings.TrimLeft(trimmed, "`~")), " ")
+			add(r.codeBlock(code, lang))
+		case isRule(trimmed):
+			rule := defaultWidth
+			if width > 0 {
+				rule = width
+			}
+
+			add([]string{r.Renderer.Text(r.Theme.Rule, strings.Repeat("─", rule))})
+
+			i++
+		case headingLevel(trimmed) > 0:
+			add(r.heading(trimmed, width))
+
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+
+			quote, i = blockQuote(lines, i)
+			add(r.blockQuote(quote, width))
+		case listMarker(line).ok:
+			var items []listItem
+
+			items, i = list(lines, i)
+			add(r.list(items, width))
+		case isTable(lines, i):
+			var rows []string
+
+			rows, i = table(lines, i)
+			add(r.table(rows))
+		default:
+			var paragraph []string
+
+			paragraph, i = paragraphLines(lines, i)
+			add(r.paragraph(strings.Join(paragraph, " "), 0, width))
+		}
+	}
+
+	return out
+}
+
+// paragraph renders a paragraph of inline Markdown text, wrapped to width, all in the base style.
+func (r Renderer) paragraph(text string, base hue.Style, width int) []string {
+	wrapped := hue.Wrapper{Width: width}.Text(r.inline(text, base))
+	return strings.Split(wrapped, "\n")
+}
+
+// heading renders a heading line e.g. "## Usage".
+func (r Renderer) heading(line string, width int) []string {
+	level := headingLevel(line)
+
+	text := strings.TrimSpace(line[level:])
+	// Optional closing sequence of #'s e.g. "## Usage ##"
+	if closed := strings.TrimRight(text, "#"); closed == "" || strings.HasSuffix(closed, " ") {
+		text = strings.TrimSpace(closed)
+	}
+
+	style := r.Theme.Heading
+	if level == 1 {
+		style = r.Theme.Heading1
+	}
+
+	return r.paragraph(text, style, width)
+}
+
+// codeBlock renders the lines of a fenced code block in the given language, indented
+// but never wrapped.
+func (r Renderer) codeBlock(code []string, lang string) []string {
+	const indent = "  "
+
+	src := strings.Join(code, "\n")
+
+	var highlighted string
+
+	switch strings.ToLower(lang) {
+	case "json", "jsonc":
+		highlighted = highlight.Highlighter{Renderer: r.Renderer, Language: highlight.JSON, Theme: r.Theme.Syntax}.Text(src)
+	case "yaml", "yml":
+		highlighted = highlight.Highlighter{Renderer: r.Renderer, Language: highlight.YAML, Theme: r.Theme.Syntax}.Text(src)
+	case "go", "golang":
+		highlighted = highlight.Highlighter{Renderer: r.Renderer, Language: highlight.Go, Theme: r.Theme.Syntax}.Text(src)
+	default:
+		highlighted = r.Renderer.Text(r.Theme.CodeBlock, src)
+	}
+
+	// Highlighted tokens (e.g. comments) and the code block style may span lines, a Wrapper
+	// that doesn't wrap makes each line self contained so it can be indented safely
+	out := strings.Split(hue.Wrapper{Width: -1}.Text(highlighted), "\n")
+	for i, line := range out {
+		if line != "" {
+			out[i] = indent + line
+		}
+	}
+
+	return out
+}
+
+// blockQuote renders the contents of a block quote (with the leading '>' removed) with
+// a bar down the left hand side.
+func (r Renderer) blockQuote(lines []string, width int) []string {
+	inner := r.blocks(lines, narrower(width, 2), false) //nolint: mnd // Width of the bar
+	if len(inner) == 0 {
+		inner = []string{""}
+	}
+
+	out := make([]string, 0, len(inner))
+	for _, line := range inner {
+		if line == "" {
+			out = append(out, r.Renderer.Text(r.Theme.Quote, "│"))
+		} else {
+			out = append(out, r.Renderer.Text(r.Theme.Quote, "│")+" "+line)
+		}
+	}
+
+	return out
+}
+
+// list renders the items of a single list.
+func (r Renderer) list(items []listItem, width int) []string {
+	// Ordered lists number up from the first item, pad the numbers so the items line up
+	number, _ := strconv.Atoi(items[0].marker.number)
+	widest := len(strconv.Itoa(number + len(items) - 1))
+
+	loose := false
+	for _, item := range items {
+		loose = loose || item.loose
+	}
+
+	var out []string
+
+	for i, item := range items {
+		marker := "•"
+		if items[0].marker.ordered {
+			n := strconv.Itoa(number + i)
+			marker = strings.Repeat(" ", widest-len(n)) + n + items[0].marker.delimiter
+		}
+
+		hang := strings.Repeat(" ", len([]rune(marker))+1)
+		marker = r.Renderer.Text(r.Theme.ListMarker, marker) + " "
+
+		if loose && i > 0 {
+			out = append(out, "")
+		}
+
+		body := r.blocks(item.lines, narrower(width, len(hang)), !loose)
+		if len(body) == 0 {
+			body = []string{""}
+		}
+
+		for j, line := range body {
+			switch {
+			case j == 0:
+				out = append(out, marker+line)
+			case line == "":
+				out = append(out, "")
+			default:
+				out = append(out, hang+line)
+			}
+		}
+	}
+
+	return out
+}
+
+// table renders the rows of a table, the first of which is the header and the second the
+// delimiter row (which is skipped), aligned into columns.
+//
+// As in GitHub Flavored Markdown, every row has as many cells as the header: short rows are
+// padded with empty cells and any extra cells are dropped.
+func (r Renderer) table(rows []string) []string {
+	const padding = 2
+
+	buf := &strings.Builder{}
+	tw := tabwriter.NewWriter(buf, 0, 0, padding, ' ', 0)
+	columns := len(tableCells(rows[0]))
+
+	for i, row := range rows {
+		if i == 1 {
+			continue // The delimiter row
+		}
+
+		base := hue.Style(0)
+		if i == 0 {
+			base = r.Theme.TableHeader
+		}
+
+		cells := make([]string, columns)
+		for j, cell := range tableCells(row) {
+			if j < columns {
+				cells[j] = r.inline(cell, base)
+			}
+		}
+
+		tw.Write([]byte(strings.Join(cells, "\t") + "\n")) //nolint: errcheck // Writes to a strings.Builder cannot fail
+	}
+
+	tw.Flush() //nolint: errcheck // Writes to a strings.Builder cannot fail
+
+	out := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range out {
+		out[i] = strings.TrimRight(line, " ")
+	}
+
+	return out
+}
+
+// narrower returns width reduced by n, for wrapping the contents of list items and
+// block quotes, leaving the width alone if wrapping is disabled.
+func narrower(width, n int) int {
+	if width <= 0 {
+		return width
+	}
+
+	return max(width-n, 1)
+}
+
+// splitLines splits src into lines, normalising line endings, expanding tabs and removing
+// any other control characters.
+func splitLines(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+
+	// Documents may be untrusted, so mustn't be able to write escape sequences of their own
+	src = strings.Map(func(r rune) rune {
+		if r != '\n' && (r < ' ' || (r >= 0x7f && r <= 0x9f)) {
+			return -1
+		}
+
+		return r
+	}, src)
+
+	return strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+}
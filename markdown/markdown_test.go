@@ -0,0 +1,274 @@
+package markdown_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/highlight"
+	"go.followtheprocess.codes/hue/markdown"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name  string // Name of the test case
+		src   string // Markdown source
+		want  string // Expected output
+		width int    // Width to wrap to
+	}{
+		{
+			name: "empty",
+			src:  "",
+			want: "",
+		},
+		{
+			name:  "paragraphs",
+			src:   "The quick brown fox\njumps over the lazy dog\n\nAnother paragraph",
+			width: 20,
+			want:  "The quick brown fox\njumps over the lazy\ndog\n\nAnother paragraph\n",
+		},
+		{
+			name: "headings",
+			src:  "# Title\n## Usage ##\nSome text\n#hashtag",
+			want: "Title\n\nUsage\n\nSome text #hashtag\n",
+		},
+		{
+			name: "emphasis",
+			src:  `*emphasis*, **strong**, __also strong__, snake_case_name, a * b and \*literal\*`,
+			want: "emphasis, strong, also strong, snake_case_name, a * b and *literal*\n",
+		},
+		{
+			name: "code span",
+			src:  "Run `go test` or `` `hue` `` but not *`this*`",
+			want: "Run go test or `hue` but not *this*\n",
+		},
+		{
+			name: "links",
+			src:  "See [the docs](https://go.dev \"Go\"), <https://example.com> and ![logo](logo.png)",
+			want: "See the docs (https://go.dev), https://example.com and logo (logo.png)\n",
+		},
+		{
+			name: "fenced code",
+			src:  "```shell\n$ go get hue\n\n$ hue --help\n```\nAfter",
+			want: "  $ go get hue\n\n  $ hue --help\n\nAfter\n",
+		},
+		{
+			name: "unclosed fence",
+			src:  "~~~\ncode",
+			want: "  code\n",
+		},
+		{
+			name: "tight list",
+			src:  "- one\n- two\n  continued\n  - nested\n* other list",
+			want: "• one\n• two continued\n  • nested\n\n• other list\n",
+		},
+		{
+			name: "loose list",
+			src:  "- one\n\n- two\n\n  more\n\nAfter",
+			want: "• one\n\n• two\n\n  more\n\nAfter\n",
+		},
+		{
+			name: "ordered list",
+			src:  "9. nine\n10. ten\n11) other list",
+			want: " 9. nine\n10. ten\n\n11) other list\n",
+		},
+		{
+			name: "number in a paragraph",
+			src:  "The answer is\n42. Not a list",
+			want: "The answer is 42. Not a list\n",
+		},
+		{
+			name:  "wrapped list",
+			src:   "- the quick brown fox jumps",
+			width: 12,
+			want:  "• the quick\n  brown fox\n  jumps\n",
+		},
+		{
+			name: "block quote",
+			src:  "> quoted\nlazy\n>\n> - item",
+			want: "│ quoted lazy\n│\n│ • item\n",
+		},
+		{
+			name:  "rule",
+			src:   "above\n\n* * *\nbelow",
+			width: 10,
+			want:  "above\n\n──────────\n\nbelow\n",
+		},
+		{
+			name: "table",
+			src:  "| Name | Status |\n|:-----|-------:|\n| foo | **ok** |\n| barbaz | a \\| b |\n\nAfter",
+			want: "Name    Status\nfoo     ok\nbarbaz  a | b\n\nAfter\n",
+		},
+		{
+			name: "ragged table",
+			src:  "| Name | Status | Notes |\n|---|---|---|\n| a | 1 | x |\n| bbbbbbbb |\n| c | 2 | y | extra |",
+			want: "Name      Status  Notes\na         1       x\nbbbbbbbb\nc         2       y\n",
+		},
+		{
+			name: "not a table",
+			src:  "a | b\nnot a delimiter",
+			want: "a | b not a delimiter\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(false)
+
+			width := tt.width
+			if width == 0 {
+				width = -1
+			}
+
+			got := strconv.Quote(markdown.Renderer{Theme: markdown.DefaultTheme, Width: width}.Text(tt.src))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestStyled(t *testing.T) {
+	theme := markdown.Theme{
+		Heading1:    hue.Bold,
+		Emphasis:    hue.Italic,
+		Strong:      hue.Bold,
+		Code:        hue.Cyan,
+		CodeBlock:   hue.Green,
+		Link:        hue.Blue,
+		Quote:       hue.BrightBlack,
+		ListMarker:  hue.Red,
+		TableHeader: hue.Underline,
+	}
+
+	tests := []struct {
+		name string // Name of the test case
+		src  string // Markdown source
+		want string // Expected output
+	}{
+		{
+			name: "heading",
+			src:  "# Title",
+			want: "\x1b[1mTitle\x1b[0m\n",
+		},
+		{
+			name: "nested emphasis",
+			src:  "**bold *both* `code`**",
+			want: "\x1b[1mbold \x1b[0m\x1b[1;3mboth\x1b[0m\x1b[1m \x1b[0m\x1b[1;36mcode\x1b[0m\n",
+		},
+		{
+			name: "link",
+			src:  "[go *dev*](https://go.dev)",
+			want: "\x1b]8;;https://go.dev\x1b\\\x1b[34mgo \x1b[0m\x1b[3;34mdev\x1b[0m\x1b]8;;\x1b\\\n",
+		},
+		{
+			name: "link control characters",
+			src:  "[go\a\u009b2J](https://go.dev\a\x1b]0;title)",
+			want: "\x1b]8;;https://go.dev]0;title\x1b\\\x1b[34mgo2J\x1b[0m\x1b]8;;\x1b\\\n",
+		},
+		{
+			name: "code block",
+			src:  "```\none\ntwo\n```",
+			want: "  \x1b[32mone\x1b[0m\n  \x1b[32mtwo\x1b[0m\n",
+		},
+		{
+			name: "list and quote",
+			src:  "> - item",
+			want: "\x1b[90m│\x1b[0m \x1b[31m•\x1b[0m item\n",
+		},
+		{
+			name: "table header",
+			src:  "| a | b |\n|---|---|\n| 1 | 2 |",
+			want: "\x1b[4ma\x1b[0m  \x1b[4mb\x1b[0m\n1  2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+
+			got := strconv.Quote(markdown.Renderer{Theme: theme, Width: -1}.Text(tt.src))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	hue.Enabled(false)
+
+	buf := &bytes.Buffer{}
+	if err := (markdown.Renderer{Width: 80}).Render(buf, "# Hello\n\n*world*"); err != nil {
+		t.Fatalf("Render returned an unexpected error: %v", err)
+	}
+
+	if got, want := buf.String(), "Hello\n\nworld\n"; got != want {
+		t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, want)
+	}
+}
+
+func TestLinkWrapped(t *testing.T) {
+	t.Parallel()
+
+	renderer := markdown.Renderer{Renderer: &hue.Renderer{Colour: true}, Width: 10}
+
+	got := strconv.Quote(renderer.Text("> [go dev docs](https://go.dev)"))
+	want := strconv.Quote(
+		"│ \x1b]8;;https://go.dev\x1b\\go dev\x1b]8;;\x1b\\\n" +
+			"│ \x1b]8;;https://go.dev\x1b\\docs\x1b]8;;\x1b\\\n",
+	)
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	t.Parallel()
+
+	src := "# Title\n\n[go](https://go.dev)\n\n```json\ntrue\n```"
+
+	tests := []struct {
+		renderer *hue.Renderer // Renderer to use, regardless of the package wide settings
+		name     string        // Name of the test case
+		want     string        // Expected output
+	}{
+		{
+			name:     "plain",
+			renderer: &hue.Renderer{},
+			want:     "Title\n\ngo (https://go.dev)\n\n  true\n",
+		},
+		{
+			name:     "colour",
+			renderer: &hue.Renderer{Colour: true},
+			want: "\x1b[1mTitle\x1b[0m\n\n" +
+				"\x1b]8;;https://go.dev\x1b\\\x1b[34mgo\x1b[0m\x1b]8;;\x1b\\\n\n" +
+				"  \x1b[33mtrue\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			renderer := markdown.Renderer{
+				Renderer: tt.renderer,
+				Theme:    markdown.Theme{Heading1: hue.Bold, Link: hue.Blue, Syntax: highlight.Theme{Bool: hue.Yellow}},
+				Width:    -1,
+			}
+
+			got := strconv.Quote(renderer.Text(src))
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
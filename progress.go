@@ -0,0 +1,426 @@
+package hue
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultInterval    = 100 * time.Millisecond // Time between frames when drawing to a terminal
+	defaultLogInterval = 5 * time.Second        // Time between log lines when not
+	defaultBarWidth    = 30                     // Width of a progress bar, excluding its message and statistics
+	percent            = 100                    // Multiplier to turn a fraction into a percentage
+)
+
+// Frames is a set of frames for a [Spinner], shown one after the other in a loop.
+type Frames []string
+
+var (
+	// SpinnerDots spins with Braille dots, it is the default.
+	SpinnerDots = Frames{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+	// SpinnerLine spins a line with plain ASCII characters, for terminals or fonts
+	// that don't support Braille.
+	SpinnerLine = Frames{"-", "\\", "|", "/"}
+
+	// SpinnerArc spins a quarter circle arc.
+	SpinnerArc = Frames{"◜", "◠", "◝", "◞", "◡", "◟"}
+
+	// SpinnerPulse grows and shrinks a block.
+	SpinnerPulse = Frames{"▁", "▃", "▅", "▇", "█", "▇", "▅", "▃"}
+)
+
+// Spinner shows that work is in progress when there is no way of measuring how much of it is done.
+//
+// When hue is enabled (see [Enabled]) the spinner is animated in place on a single line, otherwise
+// (e.g. when the output is piped to a file or a CI log) it degrades to writing a plain line for the
+// initial message and every subsequent change of message, so logs aren't filled with animation frames.
+//
+// The zero value is ready to use and spins [SpinnerDots] on [os.Stderr]. The exported fields must
+// not be changed after calling [Spinner.Start], but all methods may be called safely from concurrently
+// executing goroutines. Errors writing to Out are ignored.
+//
+//	spinner := &hue.Spinner{Style: hue.Cyan}
+//	spinner.Start("Fetching dependencies")
+//	defer spinner.Stop(hue.Green.Text("✓") + " Fetched dependencies")
+type Spinner struct {
+	Out      io.Writer     // Where to draw the spinner, defaults to os.Stderr
+	Frames   Frames        // Frames to animate, defaults to SpinnerDots
+	Style    Style         // Style for the spinner itself, the message is written as is
+	Interval time.Duration // Time between frames, defaults to 100ms
+	anim     animation     // Manages the render loop
+	message  string        // Message shown after the spinner
+	frame    int           // Index of the current frame
+}
+
+// Start starts the spinner, showing message alongside it.
+//
+// Calling Start on a spinner that is already running just updates the message.
+func (s *Spinner) Start(message string) {
+	s.anim.lifecycle.Lock()
+	defer s.anim.lifecycle.Unlock()
+
+	s.anim.mu.Lock()
+	defer s.anim.mu.Unlock()
+
+	// Defaulted under the lock, as the render loop and other methods read them
+	if s.Out == nil {
+		s.Out = os.Stderr
+	}
+
+	if len(s.Frames) == 0 {
+		s.Frames = SpinnerDots
+	}
+
+	if s.anim.running {
+		s.update(message)
+		return
+	}
+
+	s.message = message
+	s.frame = 0
+
+	s.anim.start(s.Out, s.Interval, defaultInterval, s.render)
+
+	if s.anim.live {
+		s.anim.draw(s.line())
+	} else {
+		s.log()
+	}
+}
+
+// Update changes the message shown alongside the spinner.
+func (s *Spinner) Update(message string) {
+	s.anim.mu.Lock()
+	defer s.anim.mu.Unlock()
+
+	s.update(message)
+}
+
+// Stop stops the spinner and clears its line, writing final in its place if not empty.
+//
+// Stop is a no-op if the spinner is not running.
+func (s *Spinner) Stop(final string) {
+	s.anim.stop(func() {
+		s.anim.clear()
+
+		if final != "" {
+			s.anim.write(final + "\n")
+		}
+	})
+}
+
+// update changes the message, s.anim.mu must be held.
+func (s *Spinner) update(message string) {
+	if message == s.message {
+		return
+	}
+
+	s.message = message
+
+	if !s.anim.running {
+		return
+	}
+
+	if s.anim.live {
+		s.anim.draw(s.line())
+		return
+	}
+
+	s.log()
+}
+
+// render is called by the render loop to draw the next frame, s.anim.mu is held.
+//
+// Nothing is drawn when not live, the message is only logged when it changes.
+func (s *Spinner) render() {
+	if !s.anim.live {
+		return
+	}
+
+	s.frame = (s.frame + 1) % len(s.Frames)
+	s.anim.draw(s.line())
+}
+
+// line returns the current line for the spinner.
+func (s *Spinner) line() string {
+	line := s.Style.Text(s.Frames[s.frame])
+	if s.message != "" {
+		line += " " + s.message
+	}
+
+	return line
+}
+
+// log writes the message as a plain line.
+func (s *Spinner) log() {
+	if s.message != "" {
+		s.anim.write(s.message + "\n")
+	}
+}
+
+// ProgressBar shows how much of a measurable piece of work (e.g. a download) is done, along
+// with the percentage complete, the rate of progress and an estimate of the time remaining:
+//
+//	Downloading ██████████████░░░░░░░░░░░░░░░░  47% 47/100 9.4/s ETA 6s
+//
+// When hue is enabled (see [Enabled]) the bar is redrawn in place every Interval, otherwise (e.g.
+// when the output is piped to a file or a CI log) it degrades to writing a plain line every
+// LogInterval and once more when stopped.
+//
+// The zero value is ready to use once Total is set and draws on [os.Stderr]. The exported fields
+// must not be changed after calling [ProgressBar.Start], but all methods may be called safely from
+// concurrently executing goroutines. Errors writing to Out are ignored.
+//
+//	bar := &hue.ProgressBar{Message: "Downloading", Total: 100, Style: hue.Green}
+//	bar.Start()
+//	defer bar.Stop()
+//
+//	for range 100 {
+//		bar.Add(1)
+//	}
+type ProgressBar struct {
+	Out         io.Writer     // Where to draw the bar, defaults to os.Stderr
+	Message     string        // Shown before the bar e.g. "Downloading"
+	Total       int64         // The value at which the work is complete, <= 0 shows only the count and rate
+	Width       int           // Width of the bar itself, defaults to 30
+	Interval    time.Duration // Time between redraws when drawing to a terminal, defaults to 100ms
+	LogInterval time.Duration // Time between log lines when not drawing to a terminal, defaults to 5s
+	Style       Style         // Style for the completed part of the bar
+	TrackStyle  Style         // Style for the remaining part of the bar
+	start       time.Time     // When Start was called
+	anim        animation     // Manages the render loop
+	current     int64         // Progress so far
+}
+
+// Start starts drawing the progress bar.
+//
+// Start is a no-op if the bar is already running.
+func (p *ProgressBar) Start() {
+	p.anim.lifecycle.Lock()
+	defer p.anim.lifecycle.Unlock()
+
+	p.anim.mu.Lock()
+	defer p.anim.mu.Unlock()
+
+	// Defaulted under the lock, as the render loop and other methods read them
+	if p.Out == nil {
+		p.Out = os.Stderr
+	}
+
+	if p.Width <= 0 {
+		p.Width = defaultBarWidth
+	}
+
+	if p.anim.running {
+		return
+	}
+
+	p.start = time.Now()
+
+	interval := p.Interval
+	if !enabled.Load() {
+		interval = p.LogInterval
+		if interval <= 0 {
+			interval = defaultLogInterval
+		}
+	}
+
+	p.anim.start(p.Out, interval, defaultInterval, p.render)
+
+	if p.anim.live {
+		p.anim.draw(p.line())
+	}
+}
+
+// Add adds n to the progress made.
+func (p *ProgressBar) Add(n int64) {
+	p.anim.mu.Lock()
+	defer p.anim.mu.Unlock()
+
+	p.current += n
+}
+
+// Set sets the progress made to n.
+func (p *ProgressBar) Set(n int64) {
+	p.anim.mu.Lock()
+	defer p.anim.mu.Unlock()
+
+	p.current = n
+}
+
+// Stop stops the progress bar, drawing it one final time and moving to the next line.
+//
+// Stop is a no-op if the bar is not running.
+func (p *ProgressBar) Stop() {
+	p.anim.stop(func() {
+		p.anim.clear()
+		p.anim.write(p.line() + "\n")
+	})
+}
+
+// render is called by the render loop to redraw the bar, p.anim.mu is held.
+func (p *ProgressBar) render() {
+	if p.anim.live {
+		p.anim.draw(p.line())
+		return
+	}
+
+	p.anim.write(p.line() + "\n")
+}
+
+// line returns the current line for the progress bar.
+func (p *ProgressBar) line() string {
+	var line strings.Builder
+
+	if p.Message != "" {
+		line.WriteString(p.Message)
+		line.WriteByte(' ')
+	}
+
+	elapsed := time.Since(p.start).Seconds()
+
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(p.current) / elapsed
+	}
+
+	if p.Total <= 0 {
+		fmt.Fprintf(&line, "%d %.1f/s", p.current, rate)
+		return line.String()
+	}
+
+	fraction := min(max(float64(p.current)/float64(p.Total), 0), 1)
+	filled := int(fraction * float64(p.Width))
+
+	if filled > 0 {
+		line.WriteString(p.Style.Text(strings.Repeat("█", filled)))
+	}
+
+	if filled < p.Width {
+		line.WriteString(p.TrackStyle.Text(strings.Repeat("░", p.Width-filled)))
+	}
+	fmt.Fprintf(&line, " %3d%% %d/%d %.1f/s", int(fraction*percent), p.current, p.Total, rate)
+
+	switch {
+	case p.current >= p.Total:
+		line.WriteString(" done")
+	case rate > 0:
+		remaining := time.Duration(float64(p.Total-p.current) / rate * float64(time.Second))
+		line.WriteString(" ETA " + remaining.Round(time.Second).String())
+	default:
+		line.WriteString(" ETA ?")
+	}
+
+	return line.String()
+}
+
+// animation is the render loop shared by [Spinner] and [ProgressBar].
+//
+// The mutex guards both the animation and the state of its owner, the render function passed
+// to start is always called with it held.
+//
+// Owners hold lifecycle (before mu) for the whole of starting, as stop does for the whole of
+// stopping, so a new run can't start while the last one is still writing its final output.
+type animation struct {
+	out       io.Writer     // Where to draw
+	stopped   chan struct{} // Closed to stop the render loop
+	done      chan struct{} // Closed by the render loop once it has exited
+	mu        sync.Mutex    // Guards the animation and its owner
+	lifecycle sync.Mutex    // Serialises starting and stopping
+	running   bool          // Whether the render loop is running
+	live      bool          // Whether to redraw in place (true) or write plain lines
+}
+
+// start starts the render loop, calling render every interval (or fallback if interval <= 0).
+//
+// a.lifecycle and a.mu must be held.
+func (a *animation) start(out io.Writer, interval, fallback time.Duration, render func()) {
+	if interval <= 0 {
+		interval = fallback
+	}
+
+	a.out = out
+	a.live = enabled.Load()
+	a.running = true
+	a.stopped = make(chan struct{})
+	a.done = make(chan struct{})
+
+	if a.live {
+		a.write(hideCursor)
+	}
+
+	go a.loop(interval, render, a.stopped, a.done)
+}
+
+// loop calls render every interval until stopped is closed, closing done when it exits.
+//
+// The channels are passed in rather than read from a, as a new animation may be started
+// (replacing them) as soon as this one is stopped, before the loop has exited.
+func (a *animation) loop(interval time.Duration, render func(), stopped <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopped:
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			render()
+			a.mu.Unlock()
+		}
+	}
+}
+
+// stop stops the render loop and waits for it to exit, then calls final (with a.mu held)
+// to write any final output.
+func (a *animation) stop(final func()) {
+	a.lifecycle.Lock()
+	defer a.lifecycle.Unlock()
+
+	a.mu.Lock()
+	if !a.running {
+		a.mu.Unlock()
+		return
+	}
+
+	a.running = false
+	close(a.stopped)
+	done := a.done
+	a.mu.Unlock()
+
+	<-done
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	final()
+
+	if a.live {
+		a.write(showCursor)
+	}
+}
+
+// clear clears the current line, if drawing in place.
+func (a *animation) clear() {
+	if a.live {
+		a.write("\r" + eraseLine)
+	}
+}
+
+// draw replaces the current line with line.
+func (a *animation) draw(line string) {
+	a.write("\r" + line + eraseLine)
+}
+
+// write writes s to the output, ignoring any error.
+func (a *animation) write(s string) {
+	io.WriteString(a.out, s) //nolint: errcheck // Documented that write errors are ignored
+}
@@ -0,0 +1,264 @@
+package hue_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.followtheprocess.codes/hue"
+)
+
+// syncBuffer is a bytes.Buffer that is safe to write to from the render loop
+// while the test reads it.
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.String()
+}
+
+func TestSpinnerNotLive(t *testing.T) {
+	hue.Enabled(false)
+
+	out := &syncBuffer{}
+	spinner := &hue.Spinner{Out: out, Interval: time.Hour}
+
+	spinner.Start("Fetching")
+	spinner.Update("Fetching")
+	spinner.Update("Building")
+	spinner.Stop("Done")
+	spinner.Stop("Stopped twice")
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote("Fetching\nBuilding\nDone\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestSpinnerNotLiveAnimating(t *testing.T) {
+	hue.Enabled(false)
+
+	out := &syncBuffer{}
+	spinner := &hue.Spinner{Out: out, Interval: time.Millisecond}
+
+	spinner.Start("Fetching")
+	time.Sleep(20 * time.Millisecond) //nolint: mnd // Long enough for many frames
+	spinner.Stop("Done")
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote("Fetching\nDone\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestSpinnerLive(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	spinner := &hue.Spinner{Out: out, Frames: hue.SpinnerLine, Style: hue.Cyan, Interval: time.Hour}
+
+	spinner.Start("Fetching")
+	spinner.Update("Building")
+	spinner.Stop("Done")
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote(
+		"\x1b[?25l" +
+			"\r\x1b[36m-\x1b[0m Fetching\x1b[K" +
+			"\r\x1b[36m-\x1b[0m Building\x1b[K" +
+			"\r\x1b[KDone\n\x1b[?25h",
+	)
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestSpinnerAnimates(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	spinner := &hue.Spinner{Out: out, Frames: hue.SpinnerLine, Interval: time.Millisecond}
+
+	spinner.Start("Working")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "\r| Working") {
+		if time.Now().After(deadline) {
+			t.Fatalf("spinner never reached its third frame, got %q", out.String())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	spinner.Stop("")
+
+	if got := out.String(); !strings.HasSuffix(got, "\r\x1b[K\x1b[?25h") {
+		t.Errorf("expected the line to be cleared and the cursor restored, got %q", got)
+	}
+}
+
+func TestSpinnerConcurrentStart(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	spinner := &hue.Spinner{Out: out, Interval: time.Millisecond} // Frames defaulted by Start
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			spinner.Start("Working")
+			spinner.Stop("Done")
+		})
+	}
+
+	wg.Wait()
+
+	got := out.String()
+	if !strings.HasSuffix(got, "Done\n\x1b[?25h") {
+		t.Errorf("expected the spinner to finish with Done, got %q", got)
+	}
+
+	checkRuns(t, got)
+}
+
+// checkRuns checks that each run of an animation in out, from hiding the cursor to showing
+// it again, finished before the next one started.
+func checkRuns(t *testing.T, out string) {
+	t.Helper()
+
+	const hide, show = "\x1b[?25l", "\x1b[?25h"
+
+	for i, run := range strings.Split(out, show) {
+		if i > 0 && run != "" && !strings.HasPrefix(run, hide) {
+			t.Fatalf("output written after run %d stopped: %q", i, run)
+		}
+
+		if strings.Count(run, hide) > 1 {
+			t.Fatalf("run %d started before the last one stopped: %q", i, run)
+		}
+	}
+}
+
+func TestProgressBarNotLive(t *testing.T) {
+	hue.Enabled(false)
+
+	out := &syncBuffer{}
+	bar := &hue.ProgressBar{Out: out, Message: "Downloading", Total: 100, Width: 10, LogInterval: time.Hour}
+
+	bar.Start()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			for range 10 {
+				bar.Add(1)
+			}
+		})
+	}
+
+	wg.Wait()
+	bar.Stop()
+
+	got := out.String()
+
+	if want := "Downloading ██████████ 100% 100/100 "; !strings.HasPrefix(got, want) {
+		t.Errorf("\nGot:\t%q\nWanted prefix:\t%q\n", got, want)
+	}
+
+	if !strings.HasSuffix(got, "/s done\n") {
+		t.Errorf("expected a single completed line, got %q", got)
+	}
+
+	if n := strings.Count(got, "\n"); n != 1 {
+		t.Errorf("expected 1 line, got %d: %q", n, got)
+	}
+}
+
+func TestProgressBarLive(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	bar := &hue.ProgressBar{Out: out, Total: 10, Width: 10, Style: hue.Green, Interval: time.Hour}
+
+	bar.Start()
+	bar.Set(5)
+	bar.Stop()
+
+	got := out.String()
+
+	start := "\x1b[?25l\r░░░░░░░░░░   0% 0/10 0.0/s ETA ?\x1b[K"
+	if !strings.HasPrefix(got, start) {
+		t.Errorf("\nGot:\t%q\nWanted prefix:\t%q\n", got, start)
+	}
+
+	final := "\r\x1b[K\x1b[32m█████\x1b[0m░░░░░  50% 5/10 "
+	if !strings.Contains(got, final) {
+		t.Errorf("\nGot:\t%q\nWanted to contain:\t%q\n", got, final)
+	}
+
+	if !strings.Contains(got, " ETA ") || !strings.HasSuffix(got, "\n\x1b[?25h") {
+		t.Errorf("expected an ETA and the cursor restored, got %q", got)
+	}
+}
+
+func TestProgressBarConcurrentStart(t *testing.T) {
+	hue.Enabled(true)
+
+	out := &syncBuffer{}
+	bar := &hue.ProgressBar{Out: out, Total: 10, Interval: time.Millisecond} // Width defaulted by Start
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			bar.Start()
+			bar.Add(1)
+			bar.Stop()
+		})
+	}
+
+	wg.Wait()
+
+	got := out.String()
+	if !strings.HasSuffix(got, "\n\x1b[?25h") {
+		t.Errorf("expected the bar to finish and restore the cursor, got %q", got)
+	}
+
+	checkRuns(t, got)
+}
+
+func TestProgressBarNoTotal(t *testing.T) {
+	hue.Enabled(false)
+
+	out := &syncBuffer{}
+	bar := &hue.ProgressBar{Out: out, Message: "Processed", LogInterval: time.Hour}
+
+	bar.Stop() // Not started, should be a no-op
+	bar.Start()
+	bar.Add(42)
+	bar.Stop()
+
+	got := out.String()
+	if !strings.HasPrefix(got, "Processed 42 ") || !strings.HasSuffix(got, "/s\n") {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
@@ -0,0 +1,247 @@
+package prompt
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// confirm asks a yes or no question a key at a time.
+func (p *Prompter) confirm(s *session, question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+
+	q := p.Theme.Question.Text("? " + question)
+	s.draw([]string{q + " " + p.Theme.Hint.Text(hint) + " "}, 0, -1)
+
+	for {
+		k, err := s.readKey()
+		if err != nil {
+			return false, err
+		}
+
+		answer := def
+
+		switch k.code {
+		case keyInterrupt:
+			s.finish(q)
+			return false, ErrInterrupted
+		case keyEOF:
+			s.finish(q)
+			return false, io.EOF
+		case keyEnter:
+		case keyRune:
+			switch unicode.ToLower(k.r) {
+			case 'y':
+				answer = true
+			case 'n':
+				answer = false
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+
+		s.finish(q + " " + p.Theme.Answer.Text(yesNo(answer)))
+
+		return answer, s.err
+	}
+}
+
+// selectOne asks the user to choose one option a key at a time.
+func (p *Prompter) selectOne(s *session, question string, options []string) (int, error) {
+	s.write(hideCursor)
+	defer s.write(showCursor)
+
+	q := p.Theme.Question.Text("? " + question)
+	hint := p.Theme.Hint.Text("(↑/↓ to move, enter to select)")
+	cursor := 0
+
+	for {
+		lines := []string{q + " " + hint}
+
+		start, end := page(cursor, len(options), p.pageSize())
+		for i := start; i < end; i++ {
+			if i == cursor {
+				lines = append(lines, p.Theme.Selected.Text("> "+options[i]))
+			} else {
+				lines = append(lines, "  "+options[i])
+			}
+		}
+
+		s.draw(lines, 0, -1)
+
+		k, err := s.readKey()
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case k.code == keyInterrupt:
+			s.finish(q)
+			return 0, ErrInterrupted
+		case k.code == keyEOF:
+			s.finish(q)
+			return 0, io.EOF
+		case k.code == keyUp || (k.code == keyRune && k.r == 'k'):
+			cursor = (cursor - 1 + len(options)) % len(options)
+		case k.code == keyDown || (k.code == keyRune && k.r == 'j'):
+			cursor = (cursor + 1) % len(options)
+		case k.code == keyEnter:
+			s.finish(q + " " + p.Theme.Answer.Text(options[cursor]))
+			return cursor, s.err
+		}
+	}
+}
+
+// multiSelect asks the user to choose any number of options a key at a time.
+func (p *Prompter) multiSelect(s *session, question string, options []string) ([]int, error) {
+	s.write(hideCursor)
+	defer s.write(showCursor)
+
+	q := p.Theme.Question.Text("? " + question)
+	hint := p.Theme.Hint.Text("(↑/↓ to move, space to select, enter to confirm)")
+	checked := make([]bool, len(options))
+	cursor := 0
+
+	for {
+		lines := []string{q + " " + hint}
+
+		start, end := page(cursor, len(options), p.pageSize())
+		for i := start; i < end; i++ {
+			box := "[ ] "
+			if checked[i] {
+				box = "[x] "
+			}
+
+			switch {
+			case i == cursor:
+				lines = append(lines, p.Theme.Selected.Text("> "+box+options[i]))
+			case checked[i]:
+				lines = append(lines, "  "+p.Theme.Checked.Text(box+options[i]))
+			default:
+				lines = append(lines, "  "+box+options[i])
+			}
+		}
+
+		s.draw(lines, 0, -1)
+
+		k, err := s.readKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case k.code == keyInterrupt:
+			s.finish(q)
+			return nil, ErrInterrupted
+		case k.code == keyEOF:
+			s.finish(q)
+			return nil, io.EOF
+		case k.code == keyUp || (k.code == keyRune && k.r == 'k'):
+			cursor = (cursor - 1 + len(options)) % len(options)
+		case k.code == keyDown || (k.code == keyRune && k.r == 'j'):
+			cursor = (cursor + 1) % len(options)
+		case k.code == keyRune && k.r == ' ':
+			checked[cursor] = !checked[cursor]
+		case k.code == keyEnter:
+			chosen := []int{}
+			names := []string{}
+
+			for i, ok := range checked {
+				if ok {
+					chosen = append(chosen, i)
+					names = append(names, options[i])
+				}
+			}
+
+			s.finish(q + " " + p.Theme.Answer.Text(strings.Join(names, ", ")))
+
+			return chosen, s.err
+		}
+	}
+}
+
+// input asks the user to enter some text a key at a time, hiding it if masked.
+func (p *Prompter) input(s *session, question string, validate func(string) error, masked bool) (string, error) {
+	q := p.Theme.Question.Text("? " + question)
+	width := utf8.RuneCountInString("? "+question) + 1
+
+	var (
+		text    []rune
+		problem string
+	)
+
+	for {
+		shown := string(text)
+		if masked {
+			shown = strings.Repeat("*", len(text))
+		}
+
+		lines := []string{q + " " + shown}
+		if problem != "" {
+			lines = append(lines, p.Theme.Error.Text("✗ "+problem))
+		}
+
+		s.draw(lines, 0, width+utf8.RuneCountInString(shown))
+
+		k, err := s.readKey()
+		if err != nil {
+			return "", err
+		}
+
+		switch k.code {
+		case keyInterrupt:
+			s.finish(q)
+			return "", ErrInterrupted
+		case keyEOF:
+			s.finish(q)
+			return "", io.EOF
+		case keyRune:
+			text = append(text, k.r)
+		case keyBackspace:
+			if len(text) > 0 {
+				text = text[:len(text)-1]
+			}
+		case keyEnter:
+			answer := string(text)
+
+			if validate != nil {
+				if err := validate(answer); err != nil {
+					problem = err.Error()
+					continue
+				}
+			}
+
+			if masked {
+				s.finish(q)
+			} else {
+				s.finish(q + " " + p.Theme.Answer.Text(answer))
+			}
+
+			return answer, s.err
+		default:
+		}
+	}
+}
+
+// page returns the range of options to show, keeping the cursor roughly central.
+func page(cursor, n, size int) (start, end int) {
+	start = min(max(cursor-size/2, 0), max(n-size, 0))
+	end = min(start+size, n)
+
+	return start, end
+}
+
+// yesNo returns the text for a yes or no answer.
+func yesNo(answer bool) string {
+	if answer {
+		return "yes"
+	}
+
+	return "no"
+}
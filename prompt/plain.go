@@ -0,0 +1,150 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// plainConfirm asks a yes or no question a line at a time.
+func (p *Prompter) plainConfirm(s *session, question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+
+	for {
+		s.write(p.Theme.Question.Text("? "+question) + " " + p.Theme.Hint.Text(hint) + " ")
+
+		line, err := s.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def, s.err
+		case "y", "yes":
+			return true, s.err
+		case "n", "no":
+			return false, s.err
+		}
+
+		s.write(p.Theme.Error.Text("✗ please answer y or n") + "\n")
+	}
+}
+
+// plainSelect asks the user to choose one option a line at a time, by number or name.
+func (p *Prompter) plainSelect(s *session, question string, options []string) (int, error) {
+	p.writeOptions(s, question, options)
+
+	for {
+		s.write(p.Theme.Hint.Text(fmt.Sprintf("Choose 1-%d:", len(options))) + " ")
+
+		line, err := s.readLine()
+		if err != nil {
+			return 0, err
+		}
+
+		choice, err := parseChoice(strings.TrimSpace(line), options)
+		if err == nil {
+			return choice, s.err
+		}
+
+		s.write(p.Theme.Error.Text("✗ "+err.Error()) + "\n")
+	}
+}
+
+// plainMultiSelect asks the user to choose any number of options a line at a time, as
+// a comma separated list of numbers or names.
+func (p *Prompter) plainMultiSelect(s *session, question string, options []string) ([]int, error) {
+	p.writeOptions(s, question, options)
+
+outer:
+	for {
+		s.write(p.Theme.Hint.Text(fmt.Sprintf("Choose any of 1-%d, separated by commas:", len(options))) + " ")
+
+		line, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		chosen := []int{}
+
+		for field := range strings.SplitSeq(line, ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+
+			choice, err := parseChoice(field, options)
+			if err != nil {
+				s.write(p.Theme.Error.Text("✗ "+err.Error()) + "\n")
+				continue outer
+			}
+
+			if !slices.Contains(chosen, choice) {
+				chosen = append(chosen, choice)
+			}
+		}
+
+		slices.Sort(chosen)
+
+		return chosen, s.err
+	}
+}
+
+// plainInput asks the user to enter some text a line at a time.
+func (p *Prompter) plainInput(s *session, question string, validate func(string) error) (string, error) {
+	for {
+		s.write(p.Theme.Question.Text("? "+question) + " ")
+
+		line, err := s.readLine()
+		if err != nil {
+			return "", err
+		}
+
+		if validate != nil {
+			if err := validate(line); err != nil {
+				s.write(p.Theme.Error.Text("✗ "+err.Error()) + "\n")
+				continue
+			}
+		}
+
+		return line, s.err
+	}
+}
+
+// writeOptions writes the question and a numbered list of options.
+func (p *Prompter) writeOptions(s *session, question string, options []string) {
+	s.write(p.Theme.Question.Text("? "+question) + "\n")
+
+	for i, option := range options {
+		s.write(fmt.Sprintf("  %d) %s\n", i+1, option))
+	}
+}
+
+// parseChoice returns the index of the option chosen by answer, either its number
+// (from 1) or its name.
+func parseChoice(answer string, options []string) (int, error) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n < 1 || n > len(options) {
+			return 0, fmt.Errorf("%d is not between 1 and %d", n, len(options))
+		}
+
+		return n - 1, nil
+	}
+
+	for i, option := range options {
+		if strings.EqualFold(option, answer) {
+			return i, nil
+		}
+	}
+
+	if answer == "" {
+		return 0, errors.New("please choose an option")
+	}
+
+	return 0, fmt.Errorf("%q is not one of the options", answer)
+}
@@ -0,0 +1,225 @@
+// Package prompt provides interactive prompts for CLI applications: yes/no confirmation,
+// choosing one or many of a list of options, and text and password input with validation.
+//
+// When reading from a terminal, prompts put it into raw mode and are driven by the keyboard,
+// with the arrow keys moving through options, and redraw themselves in place:
+//
+//	p := &prompt.Prompter{Theme: prompt.DefaultTheme}
+//	colour, err := p.Select("Favourite colour?", []string{"red", "green", "blue"})
+//
+// Otherwise (e.g. when input is piped in) they fall back to reading a line at a time, so
+// answers can be scripted:
+//
+//	echo 2 | mycli
+//
+// Both modes can be driven by any [io.Reader], which makes prompts easy to test by setting
+// Mode and writing the keys a user would press to an in-memory reader:
+//
+//	p := &prompt.Prompter{In: strings.NewReader("\x1b[B\r"), Out: io.Discard, Mode: prompt.Interactive}
+//	choice, _ := p.Select("Pick one", []string{"a", "b"}) // choice == 1
+package prompt // import "go.followtheprocess.codes/hue/prompt"
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	"go.followtheprocess.codes/hue"
+	"golang.org/x/term"
+)
+
+// defaultPageSize is the default number of options shown at once by Select and MultiSelect.
+const defaultPageSize = 10
+
+var (
+	// ErrInterrupted is returned when the user presses Ctrl+C during an interactive prompt.
+	ErrInterrupted = errors.New("prompt: interrupted")
+
+	// errNoOptions is returned when Select or MultiSelect are called without any options.
+	errNoOptions = errors.New("prompt: no options to choose from")
+)
+
+// Mode controls how a [Prompter] reads answers.
+type Mode int
+
+const (
+	Auto        Mode = iota // Interactive if In is a terminal, Plain otherwise
+	Interactive             // Driven a key at a time, redrawing in place. Raw mode is only enabled if In is a terminal
+	Plain                   // Read a line at a time, for when input isn't a terminal
+)
+
+// Theme is the set of styles a [Prompter] draws prompts with.
+type Theme struct {
+	Question hue.Style // The question being asked
+	Answer   hue.Style // The answer, once given
+	Selected hue.Style // The option under the cursor
+	Checked  hue.Style // Options chosen in a MultiSelect
+	Hint     hue.Style // Hints on how to answer, and defaults
+	Error    hue.Style // Validation errors
+}
+
+// DefaultTheme is a [Theme] using the basic colours.
+var DefaultTheme = Theme{
+	Question: hue.Bold,
+	Answer:   hue.Cyan,
+	Selected: hue.Cyan | hue.Bold,
+	Checked:  hue.Green,
+	Hint:     hue.BrightBlack,
+	Error:    hue.Red,
+}
+
+// Prompter asks questions. The zero value is ready to use, reading from [os.Stdin] and
+// writing prompts to [os.Stderr], without any styles.
+//
+// A Prompter must not be copied after first use, as it buffers input between prompts.
+type Prompter struct {
+	In       io.Reader     // Where to read answers from, defaults to os.Stdin
+	Out      io.Writer     // Where to write prompts, defaults to os.Stderr
+	reader   *bufio.Reader // Buffered In, kept between prompts so no input is lost
+	Theme    Theme         // Styles to draw prompts with
+	PageSize int           // Number of options shown at once by Select and MultiSelect, defaults to 10
+	Mode     Mode          // How to read answers, defaults to Auto
+}
+
+// Confirm asks a yes or no question, returning def if the user just presses enter.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	s, err := p.session()
+	if err != nil {
+		return false, err
+	}
+	defer s.close()
+
+	if s.plain {
+		return p.plainConfirm(s, question, def)
+	}
+
+	return p.confirm(s, question, def)
+}
+
+// Select asks the user to choose one of options, returning the index of their choice.
+func (p *Prompter) Select(question string, options []string) (int, error) {
+	if len(options) == 0 {
+		return 0, errNoOptions
+	}
+
+	s, err := p.session()
+	if err != nil {
+		return 0, err
+	}
+	defer s.close()
+
+	if s.plain {
+		return p.plainSelect(s, question, options)
+	}
+
+	return p.selectOne(s, question, options)
+}
+
+// MultiSelect asks the user to choose any number of options, returning the indices of
+// their choices in order.
+func (p *Prompter) MultiSelect(question string, options []string) ([]int, error) {
+	if len(options) == 0 {
+		return nil, errNoOptions
+	}
+
+	s, err := p.session()
+	if err != nil {
+		return nil, err
+	}
+	defer s.close()
+
+	if s.plain {
+		return p.plainMultiSelect(s, question, options)
+	}
+
+	return p.multiSelect(s, question, options)
+}
+
+// Input asks the user to enter some text. If validate is not nil, it is called with the text
+// entered and if it returns an error, the error is shown and the user asked again.
+func (p *Prompter) Input(question string, validate func(string) error) (string, error) {
+	s, err := p.session()
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+
+	if s.plain {
+		return p.plainInput(s, question, validate)
+	}
+
+	return p.input(s, question, validate, false)
+}
+
+// Password is like [Prompter.Input] but doesn't show what is entered. When in Plain mode,
+// hiding the input is up to whatever is providing it.
+func (p *Prompter) Password(question string, validate func(string) error) (string, error) {
+	s, err := p.session()
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+
+	if s.plain {
+		return p.plainInput(s, question, validate)
+	}
+
+	return p.input(s, question, validate, true)
+}
+
+// session prepares to ask a question, putting the terminal into raw mode if needed.
+func (p *Prompter) session() (*session, error) {
+	if p.In == nil {
+		p.In = os.Stdin
+	}
+
+	if p.Out == nil {
+		p.Out = os.Stderr
+	}
+
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.In)
+	}
+
+	s := &session{in: p.reader, out: p.Out, plain: p.Mode == Plain}
+
+	fd, terminal := terminalFd(p.In)
+	s.terminal = terminal
+
+	if p.Mode == Auto && !terminal {
+		s.plain = true
+	}
+
+	if !s.plain && terminal {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+
+		s.restore = func() { term.Restore(fd, state) } //nolint: errcheck // Nothing more we can do
+	}
+
+	return s, nil
+}
+
+// pageSize returns the number of options to show at once.
+func (p *Prompter) pageSize() int {
+	if p.PageSize <= 0 {
+		return defaultPageSize
+	}
+
+	return p.PageSize
+}
+
+// terminalFd returns the file descriptor of r, and whether it is a terminal.
+func terminalFd(r io.Reader) (int, bool) {
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok {
+		return 0, false
+	}
+
+	fd := int(f.Fd()) //nolint: gosec // File descriptors fit in an int
+
+	return fd, term.IsTerminal(fd)
+}
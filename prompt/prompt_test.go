@@ -0,0 +1,258 @@
+package prompt_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/hue/prompt"
+)
+
+const (
+	up        = "\x1b[A"
+	down      = "\x1b[B"
+	enter     = "\r"
+	backspace = "\x7f"
+	ctrlC     = "\x03"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name    string      // Name of the test case
+		input   string      // Keys pressed or lines entered
+		wantErr error       // Expected error, if any
+		mode    prompt.Mode // Mode to run the prompt in
+		def     bool        // Default answer
+		want    bool        // Expected answer
+	}{
+		{name: "interactive yes", mode: prompt.Interactive, input: "y", want: true},
+		{name: "interactive no", mode: prompt.Interactive, input: "N", def: true, want: false},
+		{name: "interactive default", mode: prompt.Interactive, input: enter, def: true, want: true},
+		{name: "interactive ignores other keys", mode: prompt.Interactive, input: "xq" + up + "y", want: true},
+		{name: "interactive interrupted", mode: prompt.Interactive, input: ctrlC, wantErr: prompt.ErrInterrupted},
+		{name: "interactive eof", mode: prompt.Interactive, input: "", wantErr: io.EOF},
+		{name: "plain yes", mode: prompt.Plain, input: "yes\n", want: true},
+		{name: "plain default", mode: prompt.Plain, input: "\n", def: true, want: true},
+		{name: "plain retry", mode: prompt.Plain, input: "maybe\nn\n", def: true, want: false},
+		{name: "plain no newline", mode: prompt.Plain, input: "y", want: true},
+		{name: "plain eof", mode: prompt.Plain, input: "", wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &prompt.Prompter{In: strings.NewReader(tt.input), Out: io.Discard, Mode: tt.mode}
+
+			got, err := p.Confirm("Continue?", tt.def)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Confirm returned error %v, wanted %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Confirm returned %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	options := []string{"red", "green", "blue"}
+
+	tests := []struct {
+		name    string      // Name of the test case
+		input   string      // Keys pressed or lines entered
+		wantErr error       // Expected error, if any
+		want    int         // Expected choice
+		mode    prompt.Mode // Mode to run the prompt in
+	}{
+		{name: "interactive first", mode: prompt.Interactive, input: enter, want: 0},
+		{name: "interactive down", mode: prompt.Interactive, input: down + down + enter, want: 2},
+		{name: "interactive wraps", mode: prompt.Interactive, input: up + enter, want: 2},
+		{name: "interactive vim keys", mode: prompt.Interactive, input: "jjk" + enter, want: 1},
+		{name: "interactive interrupted", mode: prompt.Interactive, input: down + ctrlC, wantErr: prompt.ErrInterrupted},
+		{name: "plain number", mode: prompt.Plain, input: "2\n", want: 1},
+		{name: "plain name", mode: prompt.Plain, input: "Blue\n", want: 2},
+		{name: "plain retry", mode: prompt.Plain, input: "7\npurple\n\n1\n", want: 0},
+		{name: "plain eof", mode: prompt.Plain, input: "9\n", wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &prompt.Prompter{In: strings.NewReader(tt.input), Out: io.Discard, Mode: tt.mode}
+
+			got, err := p.Select("Colour?", options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Select returned error %v, wanted %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Select returned %d, wanted %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	options := []string{"linux", "darwin", "windows"}
+
+	tests := []struct {
+		name    string      // Name of the test case
+		input   string      // Keys pressed or lines entered
+		wantErr error       // Expected error, if any
+		want    []int       // Expected choices
+		mode    prompt.Mode // Mode to run the prompt in
+	}{
+		{name: "interactive none", mode: prompt.Interactive, input: enter, want: []int{}},
+		{name: "interactive some", mode: prompt.Interactive, input: " " + down + down + " " + enter, want: []int{0, 2}},
+		{name: "interactive toggle off", mode: prompt.Interactive, input: "  " + down + " " + enter, want: []int{1}},
+		{name: "plain", mode: prompt.Plain, input: "3, linux,3\n", want: []int{0, 2}},
+		{name: "plain none", mode: prompt.Plain, input: "\n", want: []int{}},
+		{name: "plain retry", mode: prompt.Plain, input: "1,plan9\n2\n", want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &prompt.Prompter{In: strings.NewReader(tt.input), Out: io.Discard, Mode: tt.mode}
+
+			got, err := p.MultiSelect("Platforms?", options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MultiSelect returned error %v, wanted %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MultiSelect returned %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInput(t *testing.T) {
+	minLength := func(s string) error {
+		if len(s) < 3 {
+			return errors.New("must be at least 3 characters")
+		}
+
+		return nil
+	}
+
+	tests := []struct {
+		name     string      // Name of the test case
+		input    string      // Keys pressed or lines entered
+		want     string      // Expected answer
+		mode     prompt.Mode // Mode to run the prompt in
+		password bool        // Use Password rather than Input
+	}{
+		{name: "interactive", mode: prompt.Interactive, input: "hue" + enter, want: "hue"},
+		{name: "interactive backspace", mode: prompt.Interactive, input: "hux" + backspace + "e" + enter, want: "hue"},
+		{name: "interactive invalid", mode: prompt.Interactive, input: "hi" + enter + "!" + enter, want: "hi!"},
+		{name: "interactive unicode", mode: prompt.Interactive, input: "héllo" + enter, want: "héllo"},
+		{name: "interactive password", mode: prompt.Interactive, input: "secret" + enter, want: "secret", password: true},
+		{name: "plain", mode: prompt.Plain, input: "hue\r\n", want: "hue"},
+		{name: "plain invalid", mode: prompt.Plain, input: "no\nyes\n", want: "yes"},
+		{name: "plain password", mode: prompt.Plain, input: "secret\n", want: "secret", password: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			p := &prompt.Prompter{In: strings.NewReader(tt.input), Out: out, Mode: tt.mode}
+
+			ask := p.Input
+			if tt.password {
+				ask = p.Password
+			}
+
+			got, err := ask("Name?", minLength)
+			if err != nil {
+				t.Fatalf("returned an unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %q, wanted %q", got, tt.want)
+			}
+
+			if tt.password && strings.Contains(out.String(), tt.want) {
+				t.Errorf("password was shown: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestInteractiveOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := &prompt.Prompter{In: strings.NewReader(down + enter), Out: out, Mode: prompt.Interactive}
+
+	if _, err := p.Select("Colour?", []string{"red", "green"}); err != nil {
+		t.Fatalf("Select returned an unexpected error: %v", err)
+	}
+
+	got := strconv.Quote(out.String())
+	want := strconv.Quote(
+		"\x1b[?25l" +
+			"\r\x1b[J? Colour? (↑/↓ to move, enter to select)\r\n> red\r\n  green" +
+			"\r\x1b[2A\x1b[J? Colour? (↑/↓ to move, enter to select)\r\n  red\r\n> green" +
+			"\r\x1b[2A\x1b[J? Colour? green\r\n" +
+			"\x1b[?25h",
+	)
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	options := []string{"a", "b", "c", "d", "e", "f"}
+
+	out := &bytes.Buffer{}
+	p := &prompt.Prompter{In: strings.NewReader(strings.Repeat(down, 5) + enter), Out: out, Mode: prompt.Interactive, PageSize: 3}
+
+	got, err := p.Select("Letter?", options)
+	if err != nil {
+		t.Fatalf("Select returned an unexpected error: %v", err)
+	}
+
+	if got != 5 {
+		t.Errorf("Select returned %d, wanted 5", got)
+	}
+
+	// The last page shown before choosing is d, e and f
+	if want := "\r\n  d\r\n  e\r\n> f"; !strings.Contains(out.String(), want) {
+		t.Errorf("expected last page %q in output %q", want, out.String())
+	}
+}
+
+func TestSequentialPrompts(t *testing.T) {
+	// Input is buffered between prompts, so answers to later prompts mustn't be lost. In isn't
+	// a terminal so Auto falls back to Plain
+	p := &prompt.Prompter{In: strings.NewReader("y\nhue\n2\n"), Out: io.Discard}
+
+	ok, err := p.Confirm("Continue?", false)
+	if err != nil || !ok {
+		t.Fatalf("Confirm returned %v, %v", ok, err)
+	}
+
+	name, err := p.Input("Name?", nil)
+	if err != nil || name != "hue" {
+		t.Fatalf("Input returned %q, %v", name, err)
+	}
+
+	choice, err := p.Select("Pick", []string{"a", "b"})
+	if err != nil || choice != 1 {
+		t.Fatalf("Select returned %d, %v", choice, err)
+	}
+}
+
+func TestNoOptions(t *testing.T) {
+	p := &prompt.Prompter{In: strings.NewReader(""), Out: io.Discard}
+
+	if _, err := p.Select("Pick", nil); err == nil {
+		t.Error("expected an error selecting from no options")
+	}
+
+	if _, err := p.MultiSelect("Pick", nil); err == nil {
+		t.Error("expected an error selecting from no options")
+	}
+}
@@ -0,0 +1,192 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Control sequences for redrawing interactive prompts. These are written regardless of
+// whether hue is enabled, as an interactive prompt can't work without them.
+const (
+	eraseDown  = "\x1b[J"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+)
+
+// session is a single prompt in progress.
+type session struct {
+	in       *bufio.Reader // Buffered input
+	out      io.Writer     // Where to draw the prompt
+	restore  func()        // Restores the terminal from raw mode, nil if not in raw mode
+	err      error         // First error writing to out
+	row      int           // Row of the cursor within the drawn prompt, from 0
+	plain    bool          // Whether to read a line at a time
+	terminal bool          // Whether input is from a terminal, which echoes it
+}
+
+// close restores the terminal, if it was put in raw mode.
+func (s *session) close() {
+	if s.restore != nil {
+		s.restore()
+	}
+}
+
+// write writes text to the output, keeping the first error.
+func (s *session) write(text string) {
+	if s.err != nil {
+		return
+	}
+
+	_, s.err = io.WriteString(s.out, text)
+}
+
+// draw replaces the prompt drawn so far with lines, leaving the cursor at column col of
+// line row (both from 0), or at the end of the last line if col < 0.
+//
+// Lines are separated with "\r\n" as raw mode doesn't translate "\n".
+func (s *session) draw(lines []string, row, col int) {
+	var b strings.Builder
+
+	b.WriteString("\r")
+
+	if s.row > 0 {
+		b.WriteString("\x1b[" + strconv.Itoa(s.row) + "A")
+	}
+
+	b.WriteString(eraseDown)
+	b.WriteString(strings.Join(lines, "\r\n"))
+
+	s.row = len(lines) - 1
+
+	if col >= 0 {
+		if up := s.row - row; up > 0 {
+			b.WriteString("\x1b[" + strconv.Itoa(up) + "A")
+		}
+
+		b.WriteString("\x1b[" + strconv.Itoa(col+1) + "G")
+		s.row = row
+	}
+
+	s.write(b.String())
+}
+
+// finish replaces the prompt with line, its final summary, and moves to the next line.
+func (s *session) finish(line string) {
+	s.draw([]string{line}, 0, -1)
+	s.write("\r\n")
+	s.row = 0
+}
+
+// keyCode identifies a key that isn't a printable character.
+type keyCode int
+
+const (
+	keyRune keyCode = iota // A printable character
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+	keyBackspace
+	keyInterrupt
+	keyEOF
+	keyOther // Anything else, ignored
+)
+
+// key is a single key press.
+type key struct {
+	r    rune    // The character, if code is keyRune
+	code keyCode // Which key was pressed
+}
+
+// readKey reads a single key press.
+func (s *session) readKey() (key, error) {
+	r, _, err := s.in.ReadRune()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return key{code: keyEOF}, nil
+		}
+
+		return key{}, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return key{code: keyEnter}, nil
+	case 0x7f, '\b':
+		return key{code: keyBackspace}, nil
+	case 0x03: // Ctrl+C
+		return key{code: keyInterrupt}, nil
+	case 0x04: // Ctrl+D
+		return key{code: keyEOF}, nil
+	case 0x1b:
+		return s.readEscape()
+	}
+
+	if r < ' ' || r == utf8.RuneError {
+		return key{code: keyOther}, nil
+	}
+
+	return key{r: r, code: keyRune}, nil
+}
+
+// readEscape reads the rest of an escape sequence, such as an arrow key.
+func (s *session) readEscape() (key, error) {
+	if s.in.Buffered() == 0 {
+		return key{code: keyOther}, nil // Just the escape key
+	}
+
+	intro, err := s.in.ReadByte()
+	if err != nil {
+		return key{}, err
+	}
+
+	if intro != '[' && intro != 'O' {
+		return key{code: keyOther}, nil
+	}
+
+	// Skip any parameters up to the final byte
+	for {
+		b, err := s.in.ReadByte()
+		if err != nil {
+			return key{}, err
+		}
+
+		if b < 0x40 || b > 0x7e {
+			continue
+		}
+
+		switch b {
+		case 'A':
+			return key{code: keyUp}, nil
+		case 'B':
+			return key{code: keyDown}, nil
+		case 'C':
+			return key{code: keyRight}, nil
+		case 'D':
+			return key{code: keyLeft}, nil
+		default:
+			return key{code: keyOther}, nil
+		}
+	}
+}
+
+// readLine reads a line of input, without its line ending. It returns io.EOF only if the
+// input ended before anything was read.
+func (s *session) readLine() (string, error) {
+	line, err := s.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	if !s.terminal {
+		// Nothing echoed the answer, so end the prompt's line ourselves
+		s.write("\n")
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
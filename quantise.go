@@ -0,0 +1,110 @@
+package hue
+
+import (
+	"math"
+	"sync"
+)
+
+// basic is the 16 basic colour styles, in order of their xterm palette index.
+var basic = [16]Style{
+	Black, Red, Green, Yellow, Blue, Magenta, Cyan, White,
+	BrightBlack, BrightRed, BrightGreen, BrightYellow, BrightBlue, BrightMagenta, BrightCyan, BrightWhite,
+}
+
+// basicRGB is the xterm default RGB value for each of the 16 basic colours.
+var basicRGB = [16]RGB{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+// cubeLevels is the value of each of the 6 steps of each channel in the xterm colour cube.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteLab is the OKLab value of every colour in the xterm 256 colour palette, computed
+// once on first use so that finding the nearest colour is just a comparison.
+var paletteLab = sync.OnceValue(func() [256]oklab {
+	var lab [256]oklab
+	for n := range lab {
+		lab[n] = toOKLab(Xterm256(uint8(n)))
+	}
+
+	return lab
+})
+
+// Xterm256 returns the RGB value of colour n in the 256 colour xterm palette.
+//
+// The first 16 are the basic colours, whose exact values vary by terminal so the xterm
+// defaults are used. Then comes a 6x6x6 colour cube (16-231) and finally a 24 step
+// greyscale ramp (232-255).
+func Xterm256(n uint8) RGB {
+	switch {
+	case n < 16: //nolint: mnd // Basic colours
+		return basicRGB[n]
+	case n < 232: //nolint: mnd // Colour cube
+		n -= 16
+		return RGB{R: cubeLevels[n/36], G: cubeLevels[n/6%6], B: cubeLevels[n%6]}
+	default: // Greyscale ramp
+		grey := 8 + 10*(n-232)
+		return RGB{R: grey, G: grey, B: grey}
+	}
+}
+
+// Nearest256 returns the index of the colour in the 256 colour xterm palette that looks
+// closest to c, this is how [RGB] colours are shown on a terminal that supports [Level256].
+//
+// Only the colour cube and greyscale ramp (16-255) are considered, as the values of the 16
+// basic colours vary between terminals. Closeness is measured as the euclidean distance
+// between the colours in the perceptually uniform OKLab colour space, which matches how
+// different two colours look far better than their distance in RGB. Ties go to the lowest
+// index, so the result is always deterministic.
+func Nearest256(c RGB) uint8 {
+	return uint8(nearest(c, 16, 256)) //nolint: gosec // Can't overflow, index is < 256
+}
+
+// Nearest16 returns the basic colour [Style] (e.g. [Red], [BrightRed]) that looks closest
+// to c, this is how [RGB] colours are shown on a terminal that only supports [Level16].
+//
+// Closeness is measured as for [Nearest256], against the xterm default values of the
+// basic colours.
+func Nearest16(c RGB) Style {
+	return basic[nearest(c, 0, 16)]
+}
+
+// nearest returns the index of the colour in the xterm palette in the range [from, to)
+// that is perceptually closest to c.
+func nearest(c RGB, from, to int) int {
+	target := toOKLab(c)
+	lab := paletteLab()
+
+	best, bestDist := from, math.Inf(1)
+	for n := from; n < to; n++ {
+		if d := target.distance(lab[n]); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+
+	return best
+}
+
+// distance returns the squared euclidean distance between two OKLab colours.
+func (c oklab) distance(other oklab) float64 {
+	dl := c.L - other.L
+	da := c.A - other.A
+	db := c.B - other.B
+
+	return dl*dl + da*da + db*db
+}
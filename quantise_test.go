@@ -0,0 +1,131 @@
+package hue_test
+
+import (
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+// xterm is the full xterm 256 colour palette (with the default values for the 16
+// basic colours), indexed by colour number.
+var xterm = [256]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+	"#000000", "#00005f", "#000087", "#0000af", "#0000d7", "#0000ff", "#005f00", "#005f5f",
+	"#005f87", "#005faf", "#005fd7", "#005fff", "#008700", "#00875f", "#008787", "#0087af",
+	"#0087d7", "#0087ff", "#00af00", "#00af5f", "#00af87", "#00afaf", "#00afd7", "#00afff",
+	"#00d700", "#00d75f", "#00d787", "#00d7af", "#00d7d7", "#00d7ff", "#00ff00", "#00ff5f",
+	"#00ff87", "#00ffaf", "#00ffd7", "#00ffff", "#5f0000", "#5f005f", "#5f0087", "#5f00af",
+	"#5f00d7", "#5f00ff", "#5f5f00", "#5f5f5f", "#5f5f87", "#5f5faf", "#5f5fd7", "#5f5fff",
+	"#5f8700", "#5f875f", "#5f8787", "#5f87af", "#5f87d7", "#5f87ff", "#5faf00", "#5faf5f",
+	"#5faf87", "#5fafaf", "#5fafd7", "#5fafff", "#5fd700", "#5fd75f", "#5fd787", "#5fd7af",
+	"#5fd7d7", "#5fd7ff", "#5fff00", "#5fff5f", "#5fff87", "#5fffaf", "#5fffd7", "#5fffff",
+	"#870000", "#87005f", "#870087", "#8700af", "#8700d7", "#8700ff", "#875f00", "#875f5f",
+	"#875f87", "#875faf", "#875fd7", "#875fff", "#878700", "#87875f", "#878787", "#8787af",
+	"#8787d7", "#8787ff", "#87af00", "#87af5f", "#87af87", "#87afaf", "#87afd7", "#87afff",
+	"#87d700", "#87d75f", "#87d787", "#87d7af", "#87d7d7", "#87d7ff", "#87ff00", "#87ff5f",
+	"#87ff87", "#87ffaf", "#87ffd7", "#87ffff", "#af0000", "#af005f", "#af0087", "#af00af",
+	"#af00d7", "#af00ff", "#af5f00", "#af5f5f", "#af5f87", "#af5faf", "#af5fd7", "#af5fff",
+	"#af8700", "#af875f", "#af8787", "#af87af", "#af87d7", "#af87ff", "#afaf00", "#afaf5f",
+	"#afaf87", "#afafaf", "#afafd7", "#afafff", "#afd700", "#afd75f", "#afd787", "#afd7af",
+	"#afd7d7", "#afd7ff", "#afff00", "#afff5f", "#afff87", "#afffaf", "#afffd7", "#afffff",
+	"#d70000", "#d7005f", "#d70087", "#d700af", "#d700d7", "#d700ff", "#d75f00", "#d75f5f",
+	"#d75f87", "#d75faf", "#d75fd7", "#d75fff", "#d78700", "#d7875f", "#d78787", "#d787af",
+	"#d787d7", "#d787ff", "#d7af00", "#d7af5f", "#d7af87", "#d7afaf", "#d7afd7", "#d7afff",
+	"#d7d700", "#d7d75f", "#d7d787", "#d7d7af", "#d7d7d7", "#d7d7ff", "#d7ff00", "#d7ff5f",
+	"#d7ff87", "#d7ffaf", "#d7ffd7", "#d7ffff", "#ff0000", "#ff005f", "#ff0087", "#ff00af",
+	"#ff00d7", "#ff00ff", "#ff5f00", "#ff5f5f", "#ff5f87", "#ff5faf", "#ff5fd7", "#ff5fff",
+	"#ff8700", "#ff875f", "#ff8787", "#ff87af", "#ff87d7", "#ff87ff", "#ffaf00", "#ffaf5f",
+	"#ffaf87", "#ffafaf", "#ffafd7", "#ffafff", "#ffd700", "#ffd75f", "#ffd787", "#ffd7af",
+	"#ffd7d7", "#ffd7ff", "#ffff00", "#ffff5f", "#ffff87", "#ffffaf", "#ffffd7", "#ffffff",
+	"#080808", "#121212", "#1c1c1c", "#262626", "#303030", "#3a3a3a", "#444444", "#4e4e4e",
+	"#585858", "#626262", "#6c6c6c", "#767676", "#808080", "#8a8a8a", "#949494", "#9e9e9e",
+	"#a8a8a8", "#b2b2b2", "#bcbcbc", "#c6c6c6", "#d0d0d0", "#dadada", "#e4e4e4", "#eeeeee",
+}
+
+func TestXterm256(t *testing.T) {
+	for n, want := range xterm {
+		if got := hue.Xterm256(uint8(n)).String(); got != want {
+			t.Errorf("Xterm256(%d) = %s, wanted %s", n, got, want)
+		}
+	}
+}
+
+func TestNearest256Palette(t *testing.T) {
+	// Every colour in the cube and greyscale ramp must map exactly to itself, the
+	// basic colours are skipped as they aren't candidates
+	for n := 16; n < len(xterm); n++ {
+		c, err := hue.ParseHex(xterm[n])
+		if err != nil {
+			t.Fatalf("bad palette entry %d: %v", n, err)
+		}
+
+		if got := hue.Nearest256(c); int(got) != n {
+			t.Errorf("Nearest256(%s) = %d (%s), wanted %d", xterm[n], got, hue.Xterm256(got), n)
+		}
+	}
+}
+
+func TestNearest16Palette(t *testing.T) {
+	basic := [16]hue.Style{
+		hue.Black, hue.Red, hue.Green, hue.Yellow, hue.Blue, hue.Magenta, hue.Cyan, hue.White,
+		hue.BrightBlack, hue.BrightRed, hue.BrightGreen, hue.BrightYellow,
+		hue.BrightBlue, hue.BrightMagenta, hue.BrightCyan, hue.BrightWhite,
+	}
+
+	for n, want := range basic {
+		c, err := hue.ParseHex(xterm[n])
+		if err != nil {
+			t.Fatalf("bad palette entry %d: %v", n, err)
+		}
+
+		if got := hue.Nearest16(c); got != want {
+			t.Errorf("Nearest16(%s) = %d, wanted %d", xterm[n], got, want)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		name    string    // Name of the test case
+		hex     string    // Colour to quantise
+		want16  hue.Style // Expected nearest basic colour
+		want256 uint8     // Expected nearest xterm colour
+	}{
+		{name: "orange", hex: "#ff8800", want256: 208, want16: hue.BrightRed},
+		{name: "mid grey", hex: "#808080", want256: 244, want16: hue.BrightBlack},
+		{name: "navy", hex: "#000064", want256: 17, want16: hue.Blue},
+		{name: "maroon", hex: "#640000", want256: 52, want16: hue.Red},
+		{name: "pink", hex: "#ffc0cb", want256: 217, want16: hue.White},
+		{name: "near black", hex: "#1e1e1e", want256: 234, want16: hue.Black},
+		{name: "lavender", hex: "#c8c8ff", want256: 189, want16: hue.White},
+		{name: "purple", hex: "#800080", want256: 90, want16: hue.Magenta},
+		{name: "near white", hex: "#f0f0f0", want256: 255, want16: hue.White},
+		{name: "pure red", hex: "#ff0000", want256: 196, want16: hue.BrightRed},
+		{name: "pure blue", hex: "#0000ff", want256: 21, want16: hue.Blue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := hue.ParseHex(tt.hex)
+			if err != nil {
+				t.Fatalf("ParseHex returned an unexpected error: %v", err)
+			}
+
+			if got := hue.Nearest256(c); got != tt.want256 {
+				t.Errorf("Nearest256(%s) = %d (%s), wanted %d (%s)", tt.hex, got, hue.Xterm256(got), tt.want256, hue.Xterm256(tt.want256))
+			}
+
+			if got := hue.Nearest16(c); got != tt.want16 {
+				t.Errorf("Nearest16(%s) = %d, wanted %d", tt.hex, got, tt.want16)
+			}
+		})
+	}
+}
+
+func BenchmarkNearest256(b *testing.B) {
+	c := hue.RGB{R: 255, G: 136, B: 0}
+	for b.Loop() {
+		hue.Nearest256(c)
+	}
+}
@@ -0,0 +1,148 @@
+package hue
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Renderer styles text with its own settings, rather than the package wide ones set by
+// [Enabled], [SetColourLevel] and [Simulate].
+//
+// The package wide settings suit a CLI writing to a single terminal, but are shared by every
+// goroutine in the process. A Renderer allows output with and without colour to be produced
+// at the same time: e.g. in parallel tests, or in a server rendering for clients that each
+// want something different:
+//
+//	plain := hue.Renderer{}
+//	colour := hue.Renderer{Colour: true, Level: hue.LevelTrueColour}
+//
+//	fmt.Println(plain.Text(hue.Green, "ok"))  // "ok"
+//	fmt.Println(colour.Text(hue.Green, "ok")) // "\x1b[32mok\x1b[0m"
+//
+// [Box] and the renderers in the tree, diff, highlight, errfmt, markdown, slogcolor and
+// tabwriter packages accept a Renderer in place of the package wide settings. [Spinner],
+// [ProgressBar], [Live] and the prompt package draw on the process's terminal and always
+// use the package wide settings.
+//
+// The zero value renders plain text. A Renderer is a small value, safe to copy and to
+// use from concurrently executing goroutines.
+type Renderer struct {
+	Level      ColourLevel     // Range of colours the destination supports, RGB colours are downgraded to fit
+	Simulation ColourBlindness // Type of colour blindness to simulate, if any, see [Simulate]
+	Colour     bool            // Whether to colourise output
+}
+
+// DefaultRenderer returns a [Renderer] with the package wide settings at the time it is called,
+// it renders exactly as the methods on [Style] and [RGB] do.
+func DefaultRenderer() Renderer {
+	return Renderer{
+		Colour:     enabled.Load(),
+		Level:      ColourLevel(level.Load()),
+		Simulation: ColourBlindness(simulation.Load()),
+	}
+}
+
+// Text returns text styled with s, see [Style.Text].
+func (r Renderer) Text(s Style, text string) string {
+	if !r.Colour {
+		return text
+	}
+
+	if r.Simulation != NoSimulation {
+		// Colours must be translated, which the Code fast path doesn't do
+		return string(appendStyled(r, s, nil, text))
+	}
+
+	code, err := s.Code()
+	if err != nil {
+		return text
+	}
+
+	return escape + code + "m" + text + reset
+}
+
+// AppendText appends text styled with s to dst and returns the extended slice,
+// see [Style.AppendText].
+func (r Renderer) AppendText(dst []byte, s Style, text []byte) []byte {
+	return appendStyled(r, s, dst, text)
+}
+
+// AppendString is like [Renderer.AppendText] but takes the text as a string.
+func (r Renderer) AppendString(dst []byte, s Style, text string) []byte {
+	return appendStyled(r, s, dst, text)
+}
+
+// Sprint formats using the default formats for its operands and returns the string styled with s.
+// Spaces are added between operands when neither is a string.
+func (r Renderer) Sprint(s Style, a ...any) string {
+	return r.Text(s, fmt.Sprint(a...))
+}
+
+// Sprintf formats according to a format specifier and returns the string styled with s.
+func (r Renderer) Sprintf(s Style, format string, a ...any) string {
+	return r.Text(s, fmt.Sprintf(format, a...))
+}
+
+// Sprintln formats using the default formats for its operands and returns the string styled with s.
+// Spaces are always added between operands and a newline is appended.
+func (r Renderer) Sprintln(s Style, a ...any) string {
+	return r.Text(s, fmt.Sprint(a...)) + "\n"
+}
+
+// Fprint formats using the default formats for its operands and writes the result, styled
+// with s, to w. Spaces are added between operands when neither is a string. It returns the
+// number of bytes written and any write error encountered.
+func (r Renderer) Fprint(w io.Writer, s Style, a ...any) (n int, err error) {
+	return io.WriteString(w, r.Sprint(s, a...))
+}
+
+// Fprintf formats according to a format specifier and writes the result, styled with s, to w.
+// It returns the number of bytes written and any write error encountered.
+func (r Renderer) Fprintf(w io.Writer, s Style, format string, a ...any) (n int, err error) {
+	return io.WriteString(w, r.Sprintf(s, format, a...))
+}
+
+// Fprintln formats using the default formats for its operands and writes the result, styled
+// with s, to w. Spaces are always added between operands and a newline is appended. It returns
+// the number of bytes written and any write error encountered.
+func (r Renderer) Fprintln(w io.Writer, s Style, a ...any) (n int, err error) {
+	return io.WriteString(w, r.Sprintln(s, a...))
+}
+
+// RGB returns text with c as its foreground colour, see [RGB.Text].
+func (r Renderer) RGB(c RGB, text string) string {
+	if !r.Colour {
+		return text
+	}
+
+	dst := make([]byte, 0, len(text)+len(escape)+len("38;2;255;255;255m")+len(reset))
+	dst = append(dst, escape...)
+	dst = c.appendCode(dst, false, r.Level, r.Simulation)
+	dst = append(dst, 'm')
+	dst = append(dst, text...)
+	dst = append(dst, reset...)
+
+	return string(dst)
+}
+
+// rendererKey is the context key for a [Renderer].
+type rendererKey struct{}
+
+// NewContext returns a copy of ctx carrying r, retrieved with [FromContext].
+//
+// This allows the colour settings for e.g. a single request to be passed down to
+// everything that renders output for it.
+func NewContext(ctx context.Context, r Renderer) context.Context {
+	return context.WithValue(ctx, rendererKey{}, r)
+}
+
+// FromContext returns the [Renderer] carried by ctx, or the [DefaultRenderer] if it
+// doesn't carry one.
+func FromContext(ctx context.Context) Renderer {
+	if r, ok := ctx.Value(rendererKey{}).(Renderer); ok {
+		return r
+	}
+
+	return DefaultRenderer()
+}
@@ -0,0 +1,212 @@
+package hue_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestRenderer(t *testing.T) {
+	orange := hue.RGB{R: 255, G: 136}
+
+	tests := []struct {
+		name   string        // Name of the test case
+		render func() string // Render some text with the renderer under test
+		want   string        // Expected result
+	}{
+		{
+			name:   "zero value",
+			render: func() string { return hue.Renderer{}.Text(hue.Green|hue.Bold, "hello") },
+			want:   "hello",
+		},
+		{
+			name:   "text",
+			render: func() string { return hue.Renderer{Colour: true}.Text(hue.Green|hue.Bold, "hello") },
+			want:   "\x1b[1;32mhello\x1b[0m",
+		},
+		{
+			name:   "invalid style",
+			render: func() string { return hue.Renderer{Colour: true}.Text(0, "hello") },
+			want:   "hello",
+		},
+		{
+			name: "append string",
+			render: func() string {
+				return string(hue.Renderer{Colour: true}.AppendString([]byte("> "), hue.Red, "hello"))
+			},
+			want: "> \x1b[31mhello\x1b[0m",
+		},
+		{
+			name: "append text",
+			render: func() string {
+				return string(hue.Renderer{}.AppendText([]byte("> "), hue.Red, []byte("hello")))
+			},
+			want: "> hello",
+		},
+		{
+			name:   "sprintf",
+			render: func() string { return hue.Renderer{Colour: true}.Sprintf(hue.Cyan, "%d items", 3) },
+			want:   "\x1b[36m3 items\x1b[0m",
+		},
+		{
+			name:   "sprintln",
+			render: func() string { return hue.Renderer{Colour: true}.Sprintln(hue.Cyan, "done") },
+			want:   "\x1b[36mdone\x1b[0m\n",
+		},
+		{
+			name:   "rgb truecolour",
+			render: func() string { return hue.Renderer{Colour: true, Level: hue.LevelTrueColour}.RGB(orange, "hi") },
+			want:   "\x1b[38;2;255;136;0mhi\x1b[0m",
+		},
+		{
+			name:   "rgb 256",
+			render: func() string { return hue.Renderer{Colour: true, Level: hue.Level256}.RGB(orange, "hi") },
+			want:   "\x1b[38;5;208mhi\x1b[0m",
+		},
+		{
+			name: "simulated",
+			render: func() string {
+				return hue.Renderer{Colour: true, Level: hue.Level256, Simulation: hue.Deuteranopia}.Text(hue.Bold, "hi")
+			},
+			want: "\x1b[1mhi\x1b[0m",
+		},
+		{
+			name:   "hyperlink",
+			render: func() string { return hue.Renderer{}.Hyperlink("https://go.dev", "Go") },
+			want:   "Go (https://go.dev)",
+		},
+		{
+			name: "control",
+			render: func() string {
+				return hue.Renderer{Colour: true}.Control(hue.CursorUp(2)) + hue.Renderer{}.Control(hue.HideCursor)
+			},
+			want: "\x1b[2A",
+		},
+		{
+			name: "append control",
+			render: func() string {
+				dst := hue.Renderer{Colour: true}.AppendControl([]byte("> "), hue.CursorTo(3, 4))
+				return string(hue.Renderer{}.AppendControl(dst, hue.EraseLine))
+			},
+			want: "> \x1b[3;4H",
+		},
+		{
+			name: "box",
+			render: func() string {
+				return hue.Box{Style: hue.Red, Border: hue.ASCIIBorder, Renderer: &hue.Renderer{Colour: true}}.Text("x")
+			},
+			want: "\x1b[31m+-+\x1b[0m\n\x1b[31m|\x1b[0mx\x1b[31m|\x1b[0m\n\x1b[31m+-+\x1b[0m",
+		},
+		{
+			name: "box plain",
+			render: func() string {
+				return hue.Box{Style: hue.Red, Border: hue.ASCIIBorder, Renderer: &hue.Renderer{}}.Text("x")
+			},
+			want: "+-+\n|x|\n+-+",
+		},
+		{
+			name:   "gradient disabled",
+			render: func() string { return hue.Renderer{}.Gradient("hello", orange, hue.RGB{B: 255}) },
+			want:   "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := strconv.Quote(tt.render())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestRendererFprint(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	r := hue.Renderer{Colour: true}
+
+	r.Fprint(buf, hue.Green, "a", "b")
+	r.Fprintf(buf, hue.Red, " %s ", "c")
+	r.Fprintln(buf, hue.Blue, "d")
+
+	got := strconv.Quote(buf.String())
+	want := strconv.Quote("\x1b[32mab\x1b[0m\x1b[31m c \x1b[0m\x1b[34md\x1b[0m\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestRendererIgnoresGlobal(t *testing.T) {
+	// Flip the package wide setting while renderers with fixed settings are in use,
+	// they must never be affected by it
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		for i := range 1000 {
+			hue.Enabled(i%2 == 0)
+		}
+	})
+
+	for range 4 {
+		wg.Go(func() {
+			for range 1000 {
+				if got := (hue.Renderer{}).Text(hue.Green, "plain"); got != "plain" {
+					t.Errorf("plain renderer returned %q", got)
+					return
+				}
+
+				if got := (hue.Renderer{Colour: true}).Text(hue.Green, "colour"); got != "\x1b[32mcolour\x1b[0m" {
+					t.Errorf("colour renderer returned %q", got)
+					return
+				}
+
+				if got := (hue.Renderer{}).Control(hue.EraseLine); got != "" {
+					t.Errorf("plain renderer returned control %q", got)
+					return
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+}
+
+func TestDefaultRenderer(t *testing.T) {
+	hue.Enabled(true)
+	hue.SetColourLevel(hue.Level256)
+
+	r := hue.DefaultRenderer()
+	if !r.Colour || r.Level != hue.Level256 || r.Simulation != hue.NoSimulation {
+		t.Errorf("DefaultRenderer() = %+v, wanted the package wide settings", r)
+	}
+
+	if got, want := r.Text(hue.Green, "ok"), hue.Green.Text("ok"); got != want {
+		t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, want)
+	}
+}
+
+func TestContext(t *testing.T) {
+	hue.Enabled(true)
+
+	if got := hue.FromContext(context.Background()); got != hue.DefaultRenderer() {
+		t.Errorf("FromContext with no renderer = %+v, wanted the DefaultRenderer", got)
+	}
+
+	plain := hue.Renderer{Level: hue.LevelTrueColour}
+	ctx := hue.NewContext(context.Background(), plain)
+
+	if got := hue.FromContext(ctx); got != plain {
+		t.Errorf("FromContext = %+v, wanted %+v", got, plain)
+	}
+}
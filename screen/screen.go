@@ -0,0 +1,687 @@
+// Package screen implements an in-memory virtual terminal, for testing what styled or animated
+// output actually shows rather than the bytes written to produce it.
+//
+// A [Screen] is a fixed size grid of cells, each with a rune and a [Style]. Output written to it
+// is interpreted as a terminal would: text is drawn at the cursor, wrapping at the right hand edge
+// and scrolling at the bottom, and escape sequences move the cursor, erase and set styles. So
+// a progress bar that redraws itself a hundred times leaves just its final state on the screen:
+//
+//	s := screen.New(80, 24)
+//	bar := &hue.ProgressBar{Out: s, Total: 10}
+//	// ...
+//	huetest.Golden(t, s.Styled())
+//
+// The cursor movement (CUU, CUD, CUF, CUB, CNL, CPL, CHA, CUP, HVP, VPA), erase (ED, EL, ECH),
+// editing (ICH, DCH, IL, DL, SU, SD), cursor saving (DECSC, DECRC) and SGR sequences are
+// supported, along with the private modes to show and hide the cursor (?25), toggle auto wrap (?7)
+// and switch to the alternate screen (?1049). The window title is kept from OSC 0 and 2 sequences,
+// other sequences are ignored.
+//
+// As on a terminal whose driver translates newlines (as they are by default), '\n' moves the
+// cursor to the start of the next line. Like [go.followtheprocess.codes/hue/tabwriter], a Screen
+// assumes every rune has a width of 1.
+package screen // import "go.followtheprocess.codes/hue/screen"
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.followtheprocess.codes/hue/ansi"
+)
+
+// tabWidth is the distance between the terminal's default tab stops.
+const tabWidth = 8
+
+// Attrs is a set of text attributes, which may be combined with bitwise OR.
+type Attrs uint8
+
+const (
+	Bold          Attrs = 1 << iota // Bold or increased intensity
+	Dim                             // Dim or decreased intensity
+	Italic                          // Italic
+	Underline                       // Underline
+	Blink                           // Blink
+	Reverse                         // Swapped foreground and background colours
+	Hidden                          // Hidden (concealed) text
+	Strikethrough                   // Strikethrough (crossed out)
+)
+
+// sgrCodes is the SGR code of each attribute, in the order of the bits of Attrs.
+var sgrCodes = [...]int{1, 2, 3, 4, 5, 7, 8, 9}
+
+// Colour is the colour of a cell, the zero value is the terminal's default colour.
+type Colour struct {
+	ansi.Colour      // The colour, if Set
+	Set         bool // Whether the colour is set, rather than the default
+}
+
+// Style is the style of a cell, the zero value is unstyled.
+type Style struct {
+	Foreground      Colour // Foreground (text) colour
+	Background      Colour // Background colour
+	UnderlineColour Colour // Colour of any underline
+	Attrs           Attrs  // Text attributes e.g. bold
+}
+
+// Cell is a single character cell of a [Screen].
+type Cell struct {
+	Style Style // Style the rune is drawn in
+	Rune  rune  // The rune in the cell, or 0 if nothing has been drawn there
+}
+
+// cursor is the position of the cursor, along with everything saved and restored with it.
+type cursor struct {
+	style Style // Style that text is drawn in
+	x     int   // Column, from 0
+	y     int   // Row, from 0
+	wrap  bool  // Whether the next rune wraps to the next line, having been drawn in the last column
+}
+
+// Screen is an in-memory virtual terminal, see the package documentation for details.
+//
+// A Screen is safe for concurrent use, and must be created with [New].
+type Screen struct {
+	parser   ansi.Parser // Parses output into tokens to apply to the screen
+	title    string      // Window title
+	cells    [][]Cell    // Rows of cells of the current screen
+	main     [][]Cell    // The main screen, while the alternate screen is in use
+	mu       sync.Mutex  // Guards everything else
+	cursor   cursor      // Current cursor
+	saved    cursor      // Cursor saved by DECSC, restored by DECRC
+	width    int         // Number of columns
+	height   int         // Number of rows
+	hidden   bool        // Whether the cursor is hidden
+	nowrap   bool        // Whether auto wrap is turned off
+	hasSaved bool        // Whether a cursor has been saved
+}
+
+// New returns a new blank [Screen] of the given number of columns and rows, with the cursor
+// in the top left. A width or height less than 1 is treated as 1.
+func New(width, height int) *Screen {
+	s := &Screen{
+		width:  max(width, 1),
+		height: max(height, 1),
+	}
+	s.cells = s.grid()
+	s.parser.Handler = s.apply
+
+	return s
+}
+
+// Write applies p to the screen as a terminal would. Escape sequences and runes may be split
+// across calls to Write. It never returns an error.
+func (s *Screen) Write(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.parser.Write(p)
+}
+
+// WriteString is like [Screen.Write] but takes a string.
+func (s *Screen) WriteString(str string) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.parser.WriteString(str)
+}
+
+// Cell returns the cell at column x and row y, both numbered from 0. It returns
+// the zero Cell if x or y is out of range.
+func (s *Screen) Cell(x, y int) Cell {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return Cell{}
+	}
+
+	return s.cells[y][x]
+}
+
+// Cursor returns the column and row of the cursor, both numbered from 0.
+func (s *Screen) Cursor() (x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor.x, s.cursor.y
+}
+
+// CursorVisible reports whether the cursor is shown, i.e. it hasn't been hidden with
+// "ESC [?25l" or it has been shown again since.
+func (s *Screen) CursorVisible() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.hidden
+}
+
+// Title returns the window title, as last set by an OSC 0 or 2 sequence.
+func (s *Screen) Title() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.title
+}
+
+// Text returns the contents of the screen as plain text, one line per row.
+//
+// Trailing spaces are trimmed from each line, and trailing empty lines from the screen, so the
+// result only has as many lines as have something on them.
+func (s *Screen) Text() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := make([]string, 0, s.height)
+
+	for _, row := range s.cells {
+		line := make([]rune, 0, s.width)
+		for _, c := range row {
+			line = append(line, c.char())
+		}
+
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+
+	return join(lines)
+}
+
+// Styled is like [Screen.Text] but includes SGR sequences to draw each cell in its style, so the
+// styles on the screen may be compared with [go.followtheprocess.codes/hue/huetest.Readable].
+//
+// Each line is self contained: any style is reset at its end. Trailing spaces are only trimmed
+// from a line if they are unstyled, as a background colour or reverse video makes them visible.
+func (s *Screen) Styled() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := make([]string, 0, s.height)
+
+	for _, row := range s.cells {
+		end := len(row)
+		for end > 0 && row[end-1].Style == (Style{}) && row[end-1].char() == ' ' {
+			end--
+		}
+
+		b := &strings.Builder{}
+
+		var style Style
+
+		for _, c := range row[:end] {
+			if c.Style != style {
+				if style != (Style{}) {
+					b.WriteString("\x1b[0m")
+				}
+
+				b.WriteString(sgr(c.Style))
+
+				style = c.Style
+			}
+
+			b.WriteRune(c.char())
+		}
+
+		if style != (Style{}) {
+			b.WriteString("\x1b[0m")
+		}
+
+		lines = append(lines, b.String())
+	}
+
+	return join(lines)
+}
+
+// apply applies a token of output to the screen, it's the Handler of the parser.
+func (s *Screen) apply(tok ansi.Token) {
+	switch tok.Kind {
+	case ansi.Text:
+		for _, r := range tok.Raw {
+			s.draw(r)
+		}
+	case ansi.Control:
+		s.control(tok.Final)
+	case ansi.Escape:
+		s.escape(tok)
+	case ansi.CSI:
+		s.csi(tok)
+	case ansi.SGR:
+		for _, attr := range tok.Attrs {
+			s.cursor.style = s.cursor.style.apply(attr)
+		}
+	case ansi.OSC:
+		if title, ok := strings.CutPrefix(tok.Data, "0;"); ok {
+			s.title = title
+		} else if title, ok := strings.CutPrefix(tok.Data, "2;"); ok {
+			s.title = title
+		}
+	}
+}
+
+// draw draws r at the cursor and moves it on.
+func (s *Screen) draw(r rune) {
+	if r == 0x7f {
+		// DEL is ignored by terminals
+		return
+	}
+
+	if s.cursor.wrap {
+		s.cursor.wrap = false
+		s.cursor.x = 0
+		s.lineFeed()
+	}
+
+	s.cells[s.cursor.y][s.cursor.x] = Cell{Rune: r, Style: s.cursor.style}
+
+	if s.cursor.x < s.width-1 {
+		s.cursor.x++
+	} else {
+		// The cursor stays in the last column until the next rune, which wraps
+		s.cursor.wrap = !s.nowrap
+	}
+}
+
+// control applies a control character.
+func (s *Screen) control(c byte) {
+	s.cursor.wrap = false
+
+	switch c {
+	case '\n':
+		s.cursor.x = 0
+		s.lineFeed()
+	case '\v', '\f':
+		s.lineFeed()
+	case '\r':
+		s.cursor.x = 0
+	case '\b':
+		s.cursor.x = max(s.cursor.x-1, 0)
+	case '\t':
+		s.cursor.x = min((s.cursor.x/tabWidth+1)*tabWidth, s.width-1)
+	}
+}
+
+// escape applies an escape sequence other than CSI, OSC or DCS e.g. ESC 7 to save the cursor.
+func (s *Screen) escape(tok ansi.Token) {
+	if tok.Intermediates != "" {
+		return
+	}
+
+	switch tok.Final {
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.cursor.wrap = false
+		s.lineFeed()
+	case 'E':
+		s.cursor.wrap = false
+		s.cursor.x = 0
+		s.lineFeed()
+	case 'M':
+		s.cursor.wrap = false
+		if s.cursor.y == 0 {
+			s.scrollDown(1)
+		} else {
+			s.cursor.y--
+		}
+	case 'c':
+		// Full reset, back to a blank screen as returned by New
+		s.cells = s.grid()
+		s.main = nil
+		s.title = ""
+		s.cursor = cursor{}
+		s.saved = cursor{}
+		s.hasSaved = false
+		s.hidden = false
+		s.nowrap = false
+	}
+}
+
+// csi applies a CSI sequence other than SGR.
+func (s *Screen) csi(tok ansi.Token) {
+	if tok.Intermediates == "?" {
+		s.mode(tok)
+		return
+	}
+
+	if tok.Intermediates != "" {
+		return
+	}
+
+	s.cursor.wrap = false
+	n := tok.Param(0, 1)
+
+	switch tok.Final {
+	case 'A':
+		s.moveTo(s.cursor.x, s.cursor.y-n)
+	case 'B':
+		s.moveTo(s.cursor.x, s.cursor.y+n)
+	case 'C':
+		s.moveTo(s.cursor.x+n, s.cursor.y)
+	case 'D':
+		s.moveTo(s.cursor.x-n, s.cursor.y)
+	case 'E':
+		s.moveTo(0, s.cursor.y+n)
+	case 'F':
+		s.moveTo(0, s.cursor.y-n)
+	case 'G':
+		s.moveTo(n-1, s.cursor.y)
+	case 'd':
+		s.moveTo(s.cursor.x, n-1)
+	case 'H', 'f':
+		s.moveTo(tok.Param(1, 1)-1, n-1)
+	case 'J':
+		s.eraseDisplay(tok.Param(0, 0))
+	case 'K':
+		s.eraseLine(tok.Param(0, 0))
+	case 'X':
+		s.erase(s.cursor.y, s.cursor.x, s.cursor.x+n)
+	case '@':
+		row := s.cells[s.cursor.y]
+		n = min(n, s.width-s.cursor.x)
+		copy(row[s.cursor.x+n:], row[s.cursor.x:])
+		s.erase(s.cursor.y, s.cursor.x, s.cursor.x+n)
+	case 'P':
+		row := s.cells[s.cursor.y]
+		n = min(n, s.width-s.cursor.x)
+		copy(row[s.cursor.x:], row[s.cursor.x+n:])
+		s.erase(s.cursor.y, s.width-n, s.width)
+	case 'L':
+		s.insertLines(s.cursor.y, n)
+	case 'M':
+		s.deleteLines(s.cursor.y, n)
+	case 'S':
+		s.scrollUp(n)
+	case 'T':
+		s.scrollDown(n)
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	}
+}
+
+// mode sets or resets the DEC private modes of a "CSI ? ... h" or "CSI ? ... l" sequence.
+func (s *Screen) mode(tok ansi.Token) {
+	if tok.Final != 'h' && tok.Final != 'l' {
+		return
+	}
+
+	set := tok.Final == 'h'
+
+	for _, param := range tok.Params {
+		switch param {
+		case 7: //nolint: mnd // DECAWM
+			s.nowrap = !set
+		case 25: //nolint: mnd // DECTCEM
+			s.hidden = !set
+		case 1049: //nolint: mnd // Alternate screen, saving the cursor
+			if set && s.main == nil {
+				s.saveCursor()
+				s.main = s.cells
+				s.cells = s.grid()
+			} else if !set && s.main != nil {
+				s.cells = s.main
+				s.main = nil
+				s.restoreCursor()
+			}
+		}
+	}
+}
+
+// moveTo moves the cursor to column x and row y, limited to the screen.
+func (s *Screen) moveTo(x, y int) {
+	s.cursor.x = min(max(x, 0), s.width-1)
+	s.cursor.y = min(max(y, 0), s.height-1)
+}
+
+// lineFeed moves the cursor down a line, scrolling the screen if it's on the last line.
+func (s *Screen) lineFeed() {
+	if s.cursor.y == s.height-1 {
+		s.scrollUp(1)
+		return
+	}
+
+	s.cursor.y++
+}
+
+// eraseDisplay applies ED: erasing from the cursor to the end of the screen (mode 0), from the start
+// of the screen to the cursor (mode 1) or the whole screen (modes 2 and 3).
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.erase(s.cursor.y, s.cursor.x, s.width)
+
+		for y := s.cursor.y + 1; y < s.height; y++ {
+			s.erase(y, 0, s.width)
+		}
+	case 1:
+		for y := range s.cursor.y {
+			s.erase(y, 0, s.width)
+		}
+
+		s.erase(s.cursor.y, 0, s.cursor.x+1)
+	case 2, 3: //nolint: mnd // Whole screen, 3 also clears scrollback which a Screen doesn't have
+		for y := range s.height {
+			s.erase(y, 0, s.width)
+		}
+	}
+}
+
+// eraseLine applies EL: erasing from the cursor to the end of the line (mode 0), from the start
+// of the line to the cursor (mode 1) or the whole line (mode 2).
+func (s *Screen) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		s.erase(s.cursor.y, s.cursor.x, s.width)
+	case 1:
+		s.erase(s.cursor.y, 0, s.cursor.x+1)
+	case 2: //nolint: mnd // Whole line
+		s.erase(s.cursor.y, 0, s.width)
+	}
+}
+
+// erase blanks the cells of row y from column start up to end.
+func (s *Screen) erase(y, start, end int) {
+	blank := s.blank()
+	row := s.cells[y]
+
+	for x := max(start, 0); x < min(end, s.width); x++ {
+		row[x] = blank
+	}
+}
+
+// scrollUp scrolls the screen up by n lines, dropping the top lines and adding blank lines at the bottom.
+func (s *Screen) scrollUp(n int) {
+	s.deleteLines(0, n)
+}
+
+// scrollDown scrolls the screen down by n lines, dropping the bottom lines and adding blank lines at the top.
+func (s *Screen) scrollDown(n int) {
+	s.insertLines(0, n)
+}
+
+// insertLines inserts n blank lines at row y, moving the lines below down and dropping those pushed off the bottom.
+func (s *Screen) insertLines(y, n int) {
+	n = min(n, s.height-y)
+	dropped := s.cells[s.height-n:]
+
+	rows := append([][]Cell(nil), s.cells[:y]...)
+	rows = append(rows, dropped...)
+	rows = append(rows, s.cells[y:s.height-n]...)
+	s.cells = rows
+
+	for i := y; i < y+n; i++ {
+		s.erase(i, 0, s.width)
+	}
+}
+
+// deleteLines deletes n lines at row y, moving the lines below up and adding blank lines at the bottom.
+func (s *Screen) deleteLines(y, n int) {
+	n = min(n, s.height-y)
+	deleted := s.cells[y : y+n]
+
+	rows := append([][]Cell(nil), s.cells[:y]...)
+	rows = append(rows, s.cells[y+n:]...)
+	rows = append(rows, deleted...)
+	s.cells = rows
+
+	for i := s.height - n; i < s.height; i++ {
+		s.erase(i, 0, s.width)
+	}
+}
+
+// saveCursor saves the cursor position and style, as DECSC.
+func (s *Screen) saveCursor() {
+	s.saved = s.cursor
+	s.hasSaved = true
+}
+
+// restoreCursor restores the cursor saved by saveCursor, or moves it to the top left
+// with no style if there isn't one, as DECRC.
+func (s *Screen) restoreCursor() {
+	if !s.hasSaved {
+		s.cursor = cursor{}
+		return
+	}
+
+	s.cursor = s.saved
+	s.moveTo(s.cursor.x, s.cursor.y)
+}
+
+// blank returns an erased cell: terminals fill erased cells with the current background colour.
+func (s *Screen) blank() Cell {
+	return Cell{Style: Style{Background: s.cursor.style.Background}}
+}
+
+// grid returns a new blank grid of cells the size of the screen.
+func (s *Screen) grid() [][]Cell {
+	cells := make([]Cell, s.width*s.height)
+	rows := make([][]Cell, s.height)
+
+	for y := range rows {
+		rows[y] = cells[y*s.width : (y+1)*s.width : (y+1)*s.width]
+	}
+
+	return rows
+}
+
+// char returns the character shown for c, a space if nothing has been drawn in it.
+func (c Cell) char() rune {
+	if c.Rune == 0 {
+		return ' '
+	}
+
+	return c.Rune
+}
+
+// apply returns the style with the attribute set by an SGR sequence applied.
+func (s Style) apply(attr ansi.Attr) Style {
+	switch attr.Kind {
+	case ansi.Reset:
+		return Style{}
+	case ansi.Bold:
+		s.Attrs |= Bold
+	case ansi.Dim:
+		s.Attrs |= Dim
+	case ansi.Italic:
+		s.Attrs |= Italic
+	case ansi.Underline:
+		s.Attrs |= Underline
+	case ansi.Blink:
+		s.Attrs |= Blink
+	case ansi.Reverse:
+		s.Attrs |= Reverse
+	case ansi.Hidden:
+		s.Attrs |= Hidden
+	case ansi.Strikethrough:
+		s.Attrs |= Strikethrough
+	case ansi.NormalIntensity:
+		s.Attrs &^= Bold | Dim
+	case ansi.NotItalic:
+		s.Attrs &^= Italic
+	case ansi.NotUnderline:
+		s.Attrs &^= Underline
+	case ansi.NotBlink:
+		s.Attrs &^= Blink
+	case ansi.NotReverse:
+		s.Attrs &^= Reverse
+	case ansi.NotHidden:
+		s.Attrs &^= Hidden
+	case ansi.NotStrikethrough:
+		s.Attrs &^= Strikethrough
+	case ansi.Foreground:
+		s.Foreground = Colour{Colour: attr.Colour, Set: true}
+	case ansi.Background:
+		s.Background = Colour{Colour: attr.Colour, Set: true}
+	case ansi.UnderlineColour:
+		s.UnderlineColour = Colour{Colour: attr.Colour, Set: true}
+	case ansi.DefaultForeground:
+		s.Foreground = Colour{}
+	case ansi.DefaultBackground:
+		s.Background = Colour{}
+	case ansi.DefaultUnderlineColour:
+		s.UnderlineColour = Colour{}
+	}
+
+	return s
+}
+
+// sgr returns the SGR sequence that sets style, or "" if style is unstyled.
+func sgr(style Style) string {
+	var params []string
+
+	for i, code := range sgrCodes {
+		if style.Attrs&(1<<i) != 0 {
+			params = append(params, strconv.Itoa(code))
+		}
+	}
+
+	params = colourParams(params, style.Foreground, 30, 90, "38")  //nolint: mnd // Foreground SGR codes
+	params = colourParams(params, style.Background, 40, 100, "48") //nolint: mnd // Background SGR codes
+
+	if c := style.UnderlineColour; c.Set {
+		// There are no codes for basic underline colours, the index is the same in the 256 colour palette
+		if c.Type == ansi.Basic {
+			c.Type = ansi.Indexed
+		}
+
+		params = colourParams(params, c, 0, 0, "58")
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// colourParams appends the SGR parameters that set c, if it is set, to params. The basic 8 colours
+// start from code normal, the bright variants from code bright and extended colours use code extended.
+func colourParams(params []string, c Colour, normal, bright int, extended string) []string {
+	if !c.Set {
+		return params
+	}
+
+	switch c.Type {
+	case ansi.Basic:
+		if c.Index >= 8 { //nolint: mnd // The bright colours follow the basic 8
+			return append(params, strconv.Itoa(bright+int(c.Index)-8))
+		}
+
+		return append(params, strconv.Itoa(normal+int(c.Index)))
+	case ansi.Indexed:
+		return append(params, extended, "5", strconv.Itoa(int(c.Index)))
+	default:
+		return append(params, extended, "2", strconv.Itoa(int(c.R)), strconv.Itoa(int(c.G)), strconv.Itoa(int(c.B)))
+	}
+}
+
+// join joins lines with newlines, dropping any trailing empty lines.
+func join(lines []string) string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
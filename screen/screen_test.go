@@ -0,0 +1,318 @@
+package screen_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/ansi"
+	"go.followtheprocess.codes/hue/screen"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name   string // Name of the test case
+		input  string // Output written to the screen
+		want   string // Expected text on the screen
+		width  int    // Width of the screen
+		height int    // Height of the screen
+	}{
+		{
+			name:   "empty",
+			input:  "",
+			want:   "",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "lines",
+			input:  "one\ntwo\n",
+			want:   "one\ntwo",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "carriage return",
+			input:  "hello\rj",
+			want:   "jello",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "wrap",
+			input:  "abcdefgh",
+			want:   "abcde\nfgh",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "wrap deferred",
+			input:  "abcde\nf",
+			want:   "abcde\nf",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "no wrap",
+			input:  "\x1b[?7labcdefgh",
+			want:   "abcdh",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "scroll",
+			input:  "1\n2\n3\n4",
+			want:   "2\n3\n4",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "tab and backspace",
+			input:  "a\tb\bc",
+			want:   "a       c",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "cursor movement",
+			input:  "\x1b[2;3Hx\x1b[Ay\x1b[2Cz\x1b[3Dw\x1b[Ev\x1b[5Gu\x1b[3dt",
+			want:   "   yw z\nv x u\n     t",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "cursor clamped",
+			input:  "\x1b[99;99Hx\x1b[99Ay",
+			want:   "         y\n\n         x",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "erase line",
+			input:  "abcdef\x1b[3G\x1b[K\nabcdef\x1b[3G\x1b[1K\nabcdef\x1b[2K",
+			want:   "ab\n   def",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "erase display",
+			input:  "one\ntwo\nthree\x1b[2;2H\x1b[J",
+			want:   "one\nt",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "erase display above",
+			input:  "one\ntwo\nthree\x1b[2;2H\x1b[1J",
+			want:   "\n  o\nthree",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "erase screen",
+			input:  "one\ntwo\x1b[2J",
+			want:   "",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "insert and delete characters",
+			input:  "abcdef\x1b[2G\x1b[2@\nabcdef\x1b[2G\x1b[2P\nabcdef\x1b[2G\x1b[2X",
+			want:   "a  bcdef\nadef\na  def",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "insert and delete lines",
+			input:  "1\n2\n3\n4\x1b[2;1H\x1b[L\x1b[4;1H\x1b[M",
+			want:   "1\n\n2",
+			width:  5,
+			height: 4,
+		},
+		{
+			name:   "scroll up and down",
+			input:  "1\n2\n3\x1b[S\x1b[2T",
+			want:   "\n\n2",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "reverse index",
+			input:  "1\n2\x1b[H\x1bMx",
+			want:   "x\n1\n2",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "save and restore",
+			input:  "a\x1b7\nbc\x1b8d\x1b[s\x1b[3;1He\x1b[uf",
+			want:   "adf\nbc\ne",
+			width:  5,
+			height: 3,
+		},
+		{
+			name:   "alternate screen",
+			input:  "main\x1b[?1049hfull screen\x1b[?1049l!",
+			want:   "main!",
+			width:  20,
+			height: 3,
+		},
+		{
+			name:   "ignored sequences",
+			input:  "\x1b]8;;https://go.dev\x1b\\Go\x1b]8;;\x1b\\\x1bPdata\x1b\\\x1b[?2004h\x1b(B!\x7f",
+			want:   "Go!",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "unicode",
+			input:  "héllo, 世界",
+			want:   "héllo, 世界",
+			width:  10,
+			height: 3,
+		},
+		{
+			name:   "reset",
+			input:  "hello\x1b]0;title\a\x1b[?25l\x1bcbye",
+			want:   "bye",
+			width:  10,
+			height: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := screen.New(tt.width, tt.height)
+			s.WriteString(tt.input) //nolint: errcheck // Never fails
+
+			got := strconv.Quote(s.Text())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestStyled(t *testing.T) {
+	tests := []struct {
+		name  string // Name of the test case
+		input string // Output written to the screen
+		want  string // Expected styled text on the screen
+	}{
+		{
+			name:  "plain",
+			input: "plain\n",
+			want:  "plain",
+		},
+		{
+			name:  "style",
+			input: "\x1b[1;32mok\x1b[0m done",
+			want:  "\x1b[1;32mok\x1b[0m done",
+		},
+		{
+			name:  "style changes",
+			input: "\x1b[31ma\x1b[1mb\x1b[22;39mc",
+			want:  "\x1b[31ma\x1b[0m\x1b[1;31mb\x1b[0mc",
+		},
+		{
+			name:  "extended colours",
+			input: "\x1b[38;5;208;48;2;1;2;3ma\x1b[4;58:5:9;97;100mb",
+			want:  "\x1b[38;5;208;48;2;1;2;3ma\x1b[0m\x1b[4;97;100;58;5;9mb\x1b[0m",
+		},
+		{
+			name:  "style overwritten",
+			input: "\x1b[31mred\x1b[0m\rb",
+			want:  "b\x1b[31med\x1b[0m",
+		},
+		{
+			name:  "style spans lines",
+			input: "\x1b[7mon\nboth\x1b[m",
+			want:  "\x1b[7mon\x1b[0m\n\x1b[7mboth\x1b[0m",
+		},
+		{
+			name:  "background erase",
+			input: "a\x1b[44m\x1b[K\x1b[0m",
+			want:  "a\x1b[44m         \x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := screen.New(10, 3)
+			s.WriteString(tt.input) //nolint: errcheck // Never fails
+
+			got := strconv.Quote(s.Styled())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	s := screen.New(10, 3)
+
+	// Split sequences and runes across writes
+	for _, chunk := range []string{"\x1b[?2", "5l\x1b]0;my ", "cli\a\x1b[1;3", "1m\xe4\xb8", "\x96x"} {
+		s.Write([]byte(chunk)) //nolint: errcheck // Never fails
+	}
+
+	if s.CursorVisible() {
+		t.Error("cursor should be hidden")
+	}
+
+	if got := s.Title(); got != "my cli" {
+		t.Errorf("Title() = %q, wanted %q", got, "my cli")
+	}
+
+	if x, y := s.Cursor(); x != 2 || y != 0 {
+		t.Errorf("Cursor() = (%d, %d), wanted (2, 0)", x, y)
+	}
+
+	want := screen.Cell{
+		Rune: '世',
+		Style: screen.Style{
+			Attrs:      screen.Bold,
+			Foreground: screen.Colour{Colour: ansi.Colour{Type: ansi.Basic, Index: 1}, Set: true},
+		},
+	}
+
+	if got := s.Cell(0, 0); got != want {
+		t.Errorf("Cell(0, 0) = %+v, wanted %+v", got, want)
+	}
+
+	if got := s.Cell(10, 0); got != (screen.Cell{}) {
+		t.Errorf("Cell(10, 0) = %+v, wanted the zero Cell", got)
+	}
+}
+
+func TestLive(t *testing.T) {
+	hue.Enabled(true)
+
+	s := screen.New(20, 5)
+	live := &hue.Live{Out: s, Interval: time.Hour}
+
+	live.Start()
+	one := live.Add("one: waiting")
+	two := live.Add("two: waiting")
+	live.Println("starting")
+	one.Update(hue.Green.Text("one: done"))
+	two.Remove()
+	live.Stop()
+
+	got := strconv.Quote(s.Styled())
+	want := strconv.Quote("starting\n\x1b[32mone: done\x1b[0m")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+
+	if !s.CursorVisible() {
+		t.Error("cursor should be shown again")
+	}
+}
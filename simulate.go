@@ -0,0 +1,171 @@
+package hue
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// simulation is the ColourBlindness being simulated, stored as a uint32 so it may be
+// read and set atomically.
+//
+// It defaults to the value of $HUE_SIMULATE, but can be explicitly set by the user via [Simulate].
+var simulation atomic.Uint32
+
+// ColourBlindness is a type of colour vision deficiency that hue can simulate, to preview how
+// a CLI's output looks to users who have it.
+type ColourBlindness uint32
+
+const (
+	NoSimulation ColourBlindness = iota // Normal colour vision, colours are shown as is
+	Protanopia                          // Red blindness, no functioning long wavelength (red) cones
+	Deuteranopia                        // Green blindness, no functioning medium wavelength (green) cones
+	Tritanopia                          // Blue blindness, no functioning short wavelength (blue) cones
+)
+
+// String implements [fmt.Stringer] for a [ColourBlindness].
+func (c ColourBlindness) String() string {
+	switch c {
+	case NoSimulation:
+		return "none"
+	case Protanopia:
+		return "protanopia"
+	case Deuteranopia:
+		return "deuteranopia"
+	case Tritanopia:
+		return "tritanopia"
+	default:
+		return fmt.Sprintf("ColourBlindness(%d)", uint32(c))
+	}
+}
+
+// Simulate sets the type of colour blindness to simulate. While set, every colour hue renders,
+// both the basic [Style] colours and [RGB] colours, is transformed to how it would appear to
+// someone with that type of colour blindness.
+//
+// The basic colours are simulated from their xterm default values, so are written as RGB
+// colours (downgraded as usual according to the [ColourLevel]).
+//
+// Simulation defaults to the value of $HUE_SIMULATE, which may be any of "protanopia",
+// "deuteranopia" or "tritanopia", so an existing CLI can be previewed without changing any code:
+//
+//	HUE_SIMULATE=deuteranopia mycli status
+//
+// Simulate may be called safely from concurrently executing goroutines.
+func Simulate(c ColourBlindness) {
+	simulation.Store(uint32(c))
+}
+
+// Simulate returns c as it would appear to someone with the given type of colour blindness.
+//
+// It uses the full severity matrices from Machado, Oliveira and Fernandes (2009),
+// "A Physiologically-based Model for Simulation of Color Vision Deficiency".
+func (c RGB) Simulate(blindness ColourBlindness) RGB {
+	var m *[3][3]float64
+
+	switch blindness {
+	case Protanopia:
+		m = &protanopia
+	case Deuteranopia:
+		m = &deuteranopia
+	case Tritanopia:
+		m = &tritanopia
+	default:
+		return c
+	}
+
+	r, g, b := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	return RGB{
+		R: fromLinear(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+		G: fromLinear(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+		B: fromLinear(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+	}
+}
+
+// Simulation matrices in linear RGB, from Machado et al. (2009) at severity 1.0.
+var (
+	protanopia = [3][3]float64{
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	}
+
+	deuteranopia = [3][3]float64{
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	}
+
+	tritanopia = [3][3]float64{
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	}
+)
+
+// appendSimulatedCode is like appendCode but writes every colour in s as the simulated
+// RGB equivalent of its xterm default value, downgraded to the given colour level.
+func (s Style) appendSimulatedCode(dst []byte, lvl ColourLevel, sim ColourBlindness) ([]byte, bool) {
+	if s == 0 || s >= maxStyle {
+		return dst, false
+	}
+
+	first := true
+	for style := Bold; style <= BrightWhiteBackground; style <<= 1 {
+		if s&style == 0 {
+			continue
+		}
+
+		if !first {
+			dst = append(dst, ';')
+		}
+
+		first = false
+
+		if i, background, ok := style.colourIndex(); ok {
+			// RGB.appendCode applies the simulation
+			dst = basicRGB[i].appendCode(dst, background, lvl, sim)
+			continue
+		}
+
+		code, err := style.Code()
+		if err != nil {
+			return dst, false
+		}
+
+		dst = append(dst, code...)
+	}
+
+	return dst, true
+}
+
+// colourIndex returns the palette index of s, a single basic colour style, and whether it
+// is a background colour. If s is not a basic colour, ok is false.
+func (s Style) colourIndex() (index int, background, ok bool) {
+	for i, style := range basic {
+		switch s {
+		case style:
+			return i, false, true
+		case style.toBackground():
+			return i, true, true
+		}
+	}
+
+	return 0, false, false
+}
+
+// autoDetectSimulation returns the type of colour blindness to simulate from $HUE_SIMULATE.
+func autoDetectSimulation() ColourBlindness {
+	switch strings.ToLower(os.Getenv("HUE_SIMULATE")) {
+	case "protanopia":
+		return Protanopia
+	case "deuteranopia":
+		return Deuteranopia
+	case "tritanopia":
+		return Tritanopia
+	default:
+		return NoSimulation
+	}
+}
@@ -0,0 +1,138 @@
+package hue_test
+
+import (
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestSimulateRGB(t *testing.T) {
+	tests := []struct {
+		name      string              // Name of the test case
+		colour    hue.RGB             // Colour to simulate
+		want      hue.RGB             // Expected result
+		blindness hue.ColourBlindness // Type of colour blindness to simulate
+	}{
+		{name: "none", colour: hue.RGB{R: 255}, blindness: hue.NoSimulation, want: hue.RGB{R: 255}},
+		{name: "protanopia red", colour: hue.RGB{R: 255}, blindness: hue.Protanopia, want: hue.RGB{R: 0x6d, G: 0x5f}},
+		{name: "deuteranopia red", colour: hue.RGB{R: 255}, blindness: hue.Deuteranopia, want: hue.RGB{R: 0xa3, G: 0x90}},
+		{
+			name:      "deuteranopia green",
+			colour:    hue.RGB{G: 255},
+			blindness: hue.Deuteranopia,
+			want:      hue.RGB{R: 0xef, G: 0xd6, B: 0x3a},
+		},
+		{
+			name:      "tritanopia blue",
+			colour:    hue.RGB{B: 255},
+			blindness: hue.Tritanopia,
+			want:      hue.RGB{R: 0x00, G: 0x6b, B: 0x96},
+		},
+		{name: "white unchanged", colour: hue.RGB{R: 255, G: 255, B: 255}, blindness: hue.Protanopia, want: hue.RGB{R: 255, G: 255, B: 255}},
+		{name: "grey unchanged", colour: hue.RGB{R: 128, G: 128, B: 128}, blindness: hue.Tritanopia, want: hue.RGB{R: 128, G: 128, B: 128}},
+		{name: "black unchanged", colour: hue.RGB{}, blindness: hue.Deuteranopia, want: hue.RGB{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.colour.Simulate(tt.blindness); got != tt.want {
+				t.Errorf("%v.Simulate(%v) = %v, wanted %v", tt.colour, tt.blindness, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateRendering(t *testing.T) {
+	tests := []struct {
+		name      string              // Name of the test case
+		text      func() string       // Renders the text under test
+		want      string              // Expected result
+		blindness hue.ColourBlindness // Type of colour blindness to simulate
+	}{
+		{
+			name:      "none",
+			text:      func() string { return (hue.Bold | hue.Red).Text("x") },
+			blindness: hue.NoSimulation,
+			want:      "\x1b[1;31mx\x1b[0m",
+		},
+		{
+			name:      "style text",
+			text:      func() string { return (hue.Bold | hue.Red | hue.GreenBackground).Text("x") },
+			blindness: hue.Deuteranopia,
+			want:      "\x1b[1;38;2;130;115;0;48;2;192;172;45mx\x1b[0m",
+		},
+		{
+			name:      "style sprint",
+			text:      func() string { return (hue.Bold | hue.Red).Sprint("x") },
+			blindness: hue.Deuteranopia,
+			want:      "\x1b[1;38;2;130;115;0mx\x1b[0m",
+		},
+		{
+			name:      "no colour",
+			text:      func() string { return (hue.Bold | hue.Underline).Text("x") },
+			blindness: hue.Protanopia,
+			want:      "\x1b[1;4mx\x1b[0m",
+		},
+		{
+			name:      "rgb",
+			text:      func() string { return hue.RGB{R: 255}.Text("x") },
+			blindness: hue.Deuteranopia,
+			want:      "\x1b[38;2;163;144;0mx\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(true)
+			hue.SetColourLevel(hue.LevelTrueColour)
+			hue.Simulate(tt.blindness)
+			t.Cleanup(func() { hue.Simulate(hue.NoSimulation) })
+
+			got := strconv.Quote(tt.text())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestColourBlindnessString(t *testing.T) {
+	tests := []struct {
+		want      string
+		blindness hue.ColourBlindness
+	}{
+		{blindness: hue.NoSimulation, want: "none"},
+		{blindness: hue.Protanopia, want: "protanopia"},
+		{blindness: hue.Deuteranopia, want: "deuteranopia"},
+		{blindness: hue.Tritanopia, want: "tritanopia"},
+		{blindness: hue.ColourBlindness(42), want: "ColourBlindness(42)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.blindness.String(); got != tt.want {
+			t.Errorf("ColourBlindness(%d).String() = %q, wanted %q", uint32(tt.blindness), got, tt.want)
+		}
+	}
+}
+
+func TestBlueOrangeThemeDistinguishable(t *testing.T) {
+	theme := hue.BlueOrangeTheme
+
+	for _, blindness := range []hue.ColourBlindness{
+		hue.NoSimulation,
+		hue.Protanopia,
+		hue.Deuteranopia,
+		hue.Tritanopia,
+	} {
+		success := theme.Success.Simulate(blindness)
+		failure := theme.Failure.Simulate(blindness)
+
+		// Distinguishable by lightness alone, regardless of hue
+		if got := hue.Contrast(success, failure); got < 1.5 {
+			t.Errorf("%v: contrast between Success (%v) and Failure (%v) = %.2f, wanted >= 1.5", blindness, success, failure, got)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+// Package slogcolor provides a [slog.Handler] for pretty, colourised console logs.
+//
+// Each record is written on a single line with its time, level and message followed by its
+// attributes as key=value pairs, styled with [hue.Style]s from a [Theme]:
+//
+//	logger := slog.New(slogcolor.New(os.Stderr, nil))
+//	logger.Info("listening", "addr", ":8080", "tls", true)
+//
+// Produces:
+//
+//	15:04:05.000 INFO  listening                                addr=:8080 tls=true
+//
+// Messages are padded to a common width so that the attributes of consecutive records line up.
+//
+// Colour is decided per handler from its destination rather than from hue's global setting, so a
+// handler writing to a terminal on [os.Stderr] is coloured even when [os.Stdout] is piped, and one
+// writing to a file is not. The colour level and any colour blindness simulation are still taken
+// from hue, see [hue.DefaultRenderer], unless the handler is given its own [hue.Renderer].
+package slogcolor // import "go.followtheprocess.codes/hue/slogcolor"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.followtheprocess.codes/hue"
+	"golang.org/x/term"
+)
+
+const (
+	// DefaultTimeFormat is the default layout for the time of each record.
+	DefaultTimeFormat = "15:04:05.000"
+
+	// DefaultMessageWidth is the default width messages are padded to.
+	DefaultMessageWidth = 40
+
+	levelWidth = 5 // Width of the widest built in level name e.g. "DEBUG"
+)
+
+// Colour controls when a [Handler] writes colour.
+type Colour int
+
+const (
+	Auto   Colour = iota // Colour if the destination is a terminal, honouring $NO_COLOR and $FORCE_COLOR
+	Always               // Always colour
+	Never                // Never colour
+)
+
+// Theme is the set of styles a [Handler] renders records with.
+type Theme struct {
+	Timestamp  hue.Style // Time of the record
+	Message    hue.Style // Log message
+	Key        hue.Style // Attribute keys, including any group prefix
+	DebugLevel hue.Style // Levels below Info
+	InfoLevel  hue.Style // Levels from Info up to Warn
+	WarnLevel  hue.Style // Levels from Warn up to Error
+	ErrorLevel hue.Style // Levels of Error and above
+	String     hue.Style // Attribute values of kind String
+	Number     hue.Style // Attribute values of kind Int64, Uint64 or Float64
+	Bool       hue.Style // Attribute values of kind Bool
+	Time       hue.Style // Attribute values of kind Time
+	Duration   hue.Style // Attribute values of kind Duration
+	Error      hue.Style // Attribute values that are errors
+	Any        hue.Style // Any other attribute values
+}
+
+// DefaultTheme is the [Theme] used when none is given in the [Options].
+var DefaultTheme = Theme{
+	Timestamp:  hue.BrightBlack,
+	Message:    hue.Bold,
+	Key:        hue.BrightBlack,
+	DebugLevel: hue.Magenta | hue.Bold,
+	InfoLevel:  hue.Cyan | hue.Bold,
+	WarnLevel:  hue.Yellow | hue.Bold,
+	ErrorLevel: hue.Red | hue.Bold,
+	Number:     hue.Blue,
+	Bool:       hue.Magenta,
+	Time:       hue.Green,
+	Duration:   hue.Green,
+	Error:      hue.Red,
+}
+
+// Options configure a [Handler], the zero value is a good default.
+type Options struct {
+	Level        slog.Leveler  // Minimum level to log, defaults to slog.LevelInfo
+	Theme        *Theme        // Styles for each part of a record, defaults to DefaultTheme
+	Renderer     *hue.Renderer // Renders the Theme's styles, defaults to hue's settings coloured according to Colour
+	TimeFormat   string        // Layout for the time of each record, defaults to DefaultTimeFormat
+	MessageWidth int           // Width to pad messages to, defaults to DefaultMessageWidth, < 0 disables padding
+	Colour       Colour        // When to colour the output, defaults to Auto
+}
+
+// Handler is a [slog.Handler] that writes colourised records to an [io.Writer].
+type Handler struct {
+	w            io.Writer    // Destination
+	mu           *sync.Mutex  // Guards w, shared with handlers derived by WithAttrs and WithGroup
+	level        slog.Leveler // Minimum level to log
+	theme        Theme        // Styles to render with
+	timeFormat   string       // Layout for the time of each record
+	prefix       string       // Key prefix from WithGroup e.g. "request.headers."
+	preformatted []byte       // Attributes from WithAttrs, already rendered
+	renderer     hue.Renderer // Renders the theme's styles
+	messageWidth int          // Width to pad messages to
+}
+
+// New returns a new [Handler] that writes to w, configured by opts which may be nil.
+func New(w io.Writer, opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	h := &Handler{
+		w:            w,
+		mu:           &sync.Mutex{},
+		level:        opts.Level,
+		theme:        DefaultTheme,
+		timeFormat:   opts.TimeFormat,
+		messageWidth: opts.MessageWidth,
+	}
+
+	if h.level == nil {
+		h.level = slog.LevelInfo
+	}
+
+	if opts.Theme != nil {
+		h.theme = *opts.Theme
+	}
+
+	if h.timeFormat == "" {
+		h.timeFormat = DefaultTimeFormat
+	}
+
+	if h.messageWidth == 0 {
+		h.messageWidth = DefaultMessageWidth
+	}
+
+	if opts.Renderer != nil {
+		h.renderer = *opts.Renderer
+	} else {
+		h.renderer = hue.DefaultRenderer()
+		h.renderer.Colour = colour(w, opts.Colour)
+	}
+
+	return h
+}
+
+// Enabled implements [slog.Handler], reporting whether the handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements [slog.Handler], writing r as a single line.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	buf := make([]byte, 0, 256) //nolint: mnd // Enough for most records
+
+	if !r.Time.IsZero() {
+		buf = h.appendStyled(buf, h.theme.Timestamp, r.Time.Format(h.timeFormat))
+		buf = append(buf, ' ')
+	}
+
+	level := r.Level.String()
+	buf = h.appendStyled(buf, h.levelStyle(r.Level), level)
+	buf = appendPadding(buf, levelWidth-len(level))
+	buf = append(buf, ' ')
+
+	buf = h.appendStyled(buf, h.theme.Message, r.Message)
+
+	if r.NumAttrs() != 0 || len(h.preformatted) != 0 {
+		buf = appendPadding(buf, h.messageWidth-utf8.RuneCountInString(r.Message))
+	}
+
+	buf = append(buf, h.preformatted...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, h.prefix, a)
+		return true
+	})
+
+	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf)
+
+	return err
+}
+
+// WithAttrs implements [slog.Handler], returning a new handler that includes attrs in every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.preformatted = slices.Clip(h.preformatted)
+
+	for _, a := range attrs {
+		h2.preformatted = h2.appendAttr(h2.preformatted, h2.prefix, a)
+	}
+
+	return &h2
+}
+
+// WithGroup implements [slog.Handler], returning a new handler that qualifies the keys of all
+// subsequent attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix += name + "."
+
+	return &h2
+}
+
+// appendAttr appends a, with its key qualified by prefix, to dst.
+func (h *Handler) appendAttr(dst []byte, prefix string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return dst
+		}
+
+		// A group with an empty key is inlined
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+
+		for _, attr := range attrs {
+			dst = h.appendAttr(dst, prefix, attr)
+		}
+
+		return dst
+	}
+
+	dst = append(dst, ' ')
+	dst = h.appendStyled(dst, h.theme.Key, prefix+a.Key)
+	dst = append(dst, '=')
+
+	return h.appendValue(dst, a.Value)
+}
+
+// appendValue appends the styled value v to dst.
+func (h *Handler) appendValue(dst []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return h.appendStyled(dst, h.theme.String, quote(v.String()))
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64:
+		return h.appendStyled(dst, h.theme.Number, v.String())
+	case slog.KindBool:
+		return h.appendStyled(dst, h.theme.Bool, v.String())
+	case slog.KindTime:
+		return h.appendStyled(dst, h.theme.Time, v.Time().Format(time.RFC3339))
+	case slog.KindDuration:
+		return h.appendStyled(dst, h.theme.Duration, v.Duration().String())
+	default:
+		if err, ok := v.Any().(error); ok {
+			return h.appendStyled(dst, h.theme.Error, quote(err.Error()))
+		}
+
+		return h.appendStyled(dst, h.theme.Any, quote(fmt.Sprint(v.Any())))
+	}
+}
+
+// appendStyled appends text to dst in the given style, if colouring.
+func (h *Handler) appendStyled(dst []byte, style hue.Style, text string) []byte {
+	return h.renderer.AppendString(dst, style, text)
+}
+
+// levelStyle returns the style for level.
+func (h *Handler) levelStyle(level slog.Level) hue.Style {
+	switch {
+	case level >= slog.LevelError:
+		return h.theme.ErrorLevel
+	case level >= slog.LevelWarn:
+		return h.theme.WarnLevel
+	case level >= slog.LevelInfo:
+		return h.theme.InfoLevel
+	default:
+		return h.theme.DebugLevel
+	}
+}
+
+// appendPadding appends n spaces to dst, if n > 0.
+func appendPadding(dst []byte, n int) []byte {
+	for range n {
+		dst = append(dst, ' ')
+	}
+
+	return dst
+}
+
+// quote quotes s if it would otherwise be ambiguous, in the same way as [slog.TextHandler].
+func quote(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
+
+// colour reports whether to write colour to w.
+func colour(w io.Writer, mode Colour) bool {
+	switch mode {
+	case Always:
+		return true
+	case Never:
+		return false
+	}
+
+	if os.Getenv("FORCE_COLOR") != "" {
+		return true
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	f, ok := w.(interface{ Fd() uintptr })
+
+	return ok && term.IsTerminal(int(f.Fd())) //nolint: gosec // File descriptors fit in an int
+}
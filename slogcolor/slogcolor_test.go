@@ -0,0 +1,262 @@
+package slogcolor_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/slogcolor"
+)
+
+func TestSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+
+	newHandler := func(*testing.T) slog.Handler {
+		buf.Reset()
+		return slogcolor.New(&buf, &slogcolor.Options{TimeFormat: time.RFC3339Nano, Colour: slogcolor.Never})
+	}
+
+	result := func(t *testing.T) map[string]any {
+		t.Helper()
+		return parse(t, buf.String())
+	}
+
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestHandler(t *testing.T) {
+	now := time.Date(2024, time.March, 14, 15, 9, 26, 535_000_000, time.UTC)
+
+	tests := []struct {
+		name    string             // Name of the test case
+		log     func(*slog.Logger) // Logs the records under test
+		options *slogcolor.Options // Options for the handler
+		want    string             // Expected output
+	}{
+		{
+			name:    "plain",
+			options: &slogcolor.Options{Colour: slogcolor.Never, MessageWidth: 10},
+			log: func(l *slog.Logger) {
+				l.Info("hello", "name", "hue", "count", 3)
+				l.Warn("quoted", "msg", "two words", "empty", "")
+				l.Debug("hidden")
+			},
+			want: "15:09:26.535 INFO  hello      name=hue count=3\n" +
+				"15:09:26.535 WARN  quoted     msg=\"two words\" empty=\"\"\n",
+		},
+		{
+			name:    "groups",
+			options: &slogcolor.Options{Colour: slogcolor.Never, MessageWidth: -1, Level: slog.LevelDebug},
+			log: func(l *slog.Logger) {
+				l = l.With("app", "hue").WithGroup("req")
+				l.Debug("done", "method", "GET", slog.Group("headers", "accept", "*/*"))
+			},
+			want: "15:09:26.535 DEBUG done app=hue req.method=GET req.headers.accept=*/*\n",
+		},
+		{
+			name:    "no attrs not padded",
+			options: &slogcolor.Options{Colour: slogcolor.Never},
+			log: func(l *slog.Logger) {
+				l.Error("boom")
+			},
+			want: "15:09:26.535 ERROR boom\n",
+		},
+		{
+			name:    "colour",
+			options: &slogcolor.Options{Colour: slogcolor.Always, MessageWidth: -1},
+			log: func(l *slog.Logger) {
+				l.Error("failed", "err", errors.New("no such file"), "retry", true, "after", time.Second)
+			},
+			want: "\x1b[90m15:09:26.535\x1b[0m \x1b[1;31mERROR\x1b[0m \x1b[1mfailed\x1b[0m" +
+				" \x1b[90merr\x1b[0m=\x1b[31m\"no such file\"\x1b[0m" +
+				" \x1b[90mretry\x1b[0m=\x1b[35mtrue\x1b[0m" +
+				" \x1b[90mafter\x1b[0m=\x1b[32m1s\x1b[0m\n",
+		},
+		{
+			name: "custom theme",
+			options: &slogcolor.Options{
+				Colour:       slogcolor.Always,
+				MessageWidth: -1,
+				Theme:        &slogcolor.Theme{InfoLevel: hue.Green, Number: hue.Yellow},
+			},
+			log: func(l *slog.Logger) {
+				l.Info("hi", "n", 1.5)
+			},
+			want: "15:09:26.535 \x1b[32mINFO\x1b[0m  hi n=\x1b[33m1.5\x1b[0m\n",
+		},
+		{
+			name: "renderer",
+			options: &slogcolor.Options{
+				Colour:       slogcolor.Never,
+				MessageWidth: -1,
+				Renderer:     &hue.Renderer{Colour: true},
+				Theme:        &slogcolor.Theme{InfoLevel: hue.Green, Number: hue.Yellow},
+			},
+			log: func(l *slog.Logger) {
+				l.With("n", 1).Info("hi")
+			},
+			want: "15:09:26.535 \x1b[32mINFO\x1b[0m  hi n=\x1b[33m1\x1b[0m\n",
+		},
+		{
+			name: "plain renderer",
+			options: &slogcolor.Options{
+				Colour:       slogcolor.Always,
+				MessageWidth: -1,
+				Renderer:     &hue.Renderer{},
+			},
+			log: func(l *slog.Logger) {
+				l.Info("hi", "n", 1)
+			},
+			want: "15:09:26.535 INFO  hi n=1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			handler := slogcolor.New(buf, tt.options)
+
+			tt.log(slog.New(fixedTime{Handler: handler, now: now}))
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestAutoNotTerminal(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+
+	buf := &bytes.Buffer{}
+	slog.New(slogcolor.New(buf, nil)).Info("hello", "a", 1)
+
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected plain text for a non terminal destination, got %q", buf.String())
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	hue.Simulate(hue.Deuteranopia)
+	hue.SetColourLevel(hue.LevelTrueColour)
+
+	t.Cleanup(func() { hue.Simulate(hue.NoSimulation) })
+
+	buf := &bytes.Buffer{}
+	theme := &slogcolor.Theme{InfoLevel: hue.Green}
+	slog.New(slogcolor.New(buf, &slogcolor.Options{Colour: slogcolor.Always, Theme: theme})).Info("hello")
+
+	// Simulated colours are written as RGB
+	if got := buf.String(); !strings.Contains(got, "\x1b[38;2;") || strings.Contains(got, "\x1b[32m") {
+		t.Errorf("expected the level to be coloured as simulated, got %q", got)
+	}
+}
+
+// fixedTime wraps a handler, setting the time of every record to now.
+type fixedTime struct {
+	slog.Handler
+
+	now time.Time
+}
+
+func (f fixedTime) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = f.now
+	return f.Handler.Handle(ctx, r)
+}
+
+func (f fixedTime) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return fixedTime{Handler: f.Handler.WithAttrs(attrs), now: f.now}
+}
+
+func (f fixedTime) WithGroup(name string) slog.Handler {
+	return fixedTime{Handler: f.Handler.WithGroup(name), now: f.now}
+}
+
+// parse parses a single line of plain output back into the map form slogtest expects.
+func parse(t *testing.T, line string) map[string]any {
+	t.Helper()
+
+	fields := split(t, strings.TrimSuffix(line, "\n"))
+	result := make(map[string]any)
+
+	if len(fields) > 0 {
+		if ts, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+			result[slog.TimeKey] = ts
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) < 2 { //nolint: mnd // Level and message
+		t.Fatalf("line %q is missing a level or message", line)
+	}
+
+	result[slog.LevelKey] = fields[0]
+	result[slog.MessageKey] = fields[1]
+
+	for _, field := range fields[2:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			t.Fatalf("malformed attribute %q in line %q", field, line)
+		}
+
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		// Nest dotted keys as groups
+		m := result
+		path := strings.Split(key, ".")
+		for _, group := range path[:len(path)-1] {
+			sub, ok := m[group].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				m[group] = sub
+			}
+
+			m = sub
+		}
+
+		m[path[len(path)-1]] = value
+	}
+
+	return result
+}
+
+// split splits line into space separated fields, keeping quoted values intact.
+func split(t *testing.T, line string) []string {
+	t.Helper()
+
+	var fields []string
+
+	for line = strings.TrimLeft(line, " "); line != ""; line = strings.TrimLeft(line, " ") {
+		end := strings.IndexByte(line, ' ')
+		if end == -1 {
+			end = len(line)
+		}
+
+		if eq := strings.IndexByte(line, '='); eq != -1 && eq < end && eq+1 < len(line) && line[eq+1] == '"' {
+			quoted, err := strconv.QuotedPrefix(line[eq+1:])
+			if err != nil {
+				t.Fatalf("bad quoted value in %q: %v", line, err)
+			}
+
+			end = eq + 1 + len(quoted)
+		}
+
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+
+	return fields
+}
@@ -0,0 +1,231 @@
+package tabwriter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"go.followtheprocess.codes/hue/ansi"
+)
+
+// Format is the format in which a [Writer] emits the cells written to it.
+type Format int
+
+const (
+	// Text is the default format, cells are padded and aligned into columns
+	// as described on [Writer].
+	Text Format = iota
+
+	// CSV emits each line as a record of comma separated values, as per RFC 4180.
+	CSV
+
+	// TSV is like CSV but values are separated by tabs.
+	TSV
+
+	// JSON emits a single JSON array with one object per line. The first line is
+	// treated as the header and its cells are used as the keys for every following
+	// line, cells beyond the width of the header are keyed by their (zero based)
+	// column index.
+	JSON
+
+	// Markdown emits a GitHub flavoured Markdown table, using the first line as
+	// the table header. If the [AlignRight] flag is set, the columns are right aligned.
+	Markdown
+)
+
+// String implements [fmt.Stringer] for a [Format].
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case CSV:
+		return "csv"
+	case TSV:
+		return "tsv"
+	case JSON:
+		return "json"
+	case Markdown:
+		return "markdown"
+	default:
+		return "Format(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// SetFormat sets the format in which the [Writer] emits its cells.
+//
+// For any format other than [Text], the tab-delimited input is treated as a table
+// of plain data: ANSI escape sequences and [Escape] characters are stripped from the
+// cell contents, empty lines and the empty trailing cell left by a tab-terminated
+// line are ignored, and no padding or styling is applied. The whole table is buffered
+// and only written to the output on [Writer.Flush].
+func (b *Writer) SetFormat(format Format) {
+	b.outFormat = format
+}
+
+// encode writes the buffered lines to the output in the structured format
+// configured by SetFormat.
+func (b *Writer) encode() {
+	b.checkContext()
+
+	rows := b.rows()
+	if len(rows) == 0 {
+		return
+	}
+
+	out := &bytes.Buffer{}
+
+	switch b.outFormat { //nolint: exhaustive // Text is handled by format
+	case CSV, TSV:
+		w := csv.NewWriter(out)
+		if b.outFormat == TSV {
+			w.Comma = '\t'
+		}
+
+		w.WriteAll(rows) //nolint: errcheck // Writes to a bytes.Buffer cannot fail
+	case JSON:
+		encodeJSON(out, rows)
+	case Markdown:
+		encodeMarkdown(out, rows, b.flags&AlignRight != 0)
+	}
+
+	b.write0(out.Bytes())
+}
+
+// rows returns the text of every cell in the buffered lines, stripped of
+// any escape sequences.
+func (b *Writer) rows() [][]string {
+	rows := make([][]string, 0, len(b.lines))
+	pos := 0
+
+	for _, line := range b.lines {
+		row := make([]string, 0, len(line))
+
+		for _, c := range line {
+			row = append(row, stripEscapes(b.buf[pos:pos+c.size]))
+			pos += c.size
+		}
+
+		// A tab-terminated line leaves an empty trailing cell
+		if n := len(row); n > 0 && row[n-1] == "" {
+			row = row[:n-1]
+		}
+
+		if len(row) == 0 {
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// encodeJSON writes rows to out as a JSON array of objects keyed by the first row.
+//
+// This is done by hand rather than through a map so that the keys keep the
+// order of the header.
+func encodeJSON(out *bytes.Buffer, rows [][]string) {
+	header, rows := rows[0], rows[1:]
+
+	out.WriteByte('[')
+
+	for i, row := range rows {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+
+		out.WriteByte('{')
+
+		for col, text := range row {
+			if col > 0 {
+				out.WriteByte(',')
+			}
+
+			key := strconv.Itoa(col)
+			if col < len(header) {
+				key = header[col]
+			}
+
+			writeJSONString(out, key)
+			out.WriteByte(':')
+			writeJSONString(out, text)
+		}
+
+		out.WriteByte('}')
+	}
+
+	out.WriteString("]\n")
+}
+
+// writeJSONString writes s to out as a quoted JSON string.
+func writeJSONString(out *bytes.Buffer, s string) {
+	// Marshalling a string cannot fail
+	quoted, _ := json.Marshal(s) //nolint: errchkjson // See above
+	out.Write(quoted)
+}
+
+// encodeMarkdown writes rows to out as a GitHub flavoured Markdown table with the
+// first row as the header.
+func encodeMarkdown(out *bytes.Buffer, rows [][]string, alignRight bool) {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	writeRow := func(row []string) {
+		out.WriteByte('|')
+
+		for col := range columns {
+			out.WriteByte(' ')
+
+			if col < len(row) {
+				out.WriteString(escapeMarkdown(row[col]))
+			}
+
+			out.WriteString(" |")
+		}
+
+		out.WriteByte('\n')
+	}
+
+	writeRow(rows[0])
+
+	delimiter := " --- |"
+	if alignRight {
+		delimiter = " ---: |"
+	}
+
+	out.WriteByte('|')
+
+	for range columns {
+		out.WriteString(delimiter)
+	}
+
+	out.WriteByte('\n')
+
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
+
+// escapeMarkdown escapes characters in text that would otherwise break a
+// Markdown table cell.
+func escapeMarkdown(text string) string {
+	if !strings.ContainsAny(text, "|\\") {
+		return text
+	}
+
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(text)
+}
+
+// stripEscapes returns text with any ANSI escape sequences and tabwriter [Escape]
+// characters removed.
+func stripEscapes(text []byte) string {
+	if bytes.IndexByte(text, escape) == -1 && bytes.IndexByte(text, Escape) == -1 {
+		return string(text)
+	}
+
+	return ansi.Strip(string(bytes.ReplaceAll(text, []byte{Escape}, nil)))
+}
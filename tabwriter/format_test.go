@@ -0,0 +1,141 @@
+package tabwriter_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tabwriter"
+)
+
+func TestFormat(t *testing.T) {
+	hue.Enabled(true)
+
+	// A typical table, tab-terminated cells, styled header and a styled cell
+	table := hue.Bold.Text("name") + "\tstatus\t\n" +
+		"foo\tok\t\n" +
+		"\n" +
+		"bar\t" + hue.Red.Text("failed") + "\t\n"
+
+	tests := []struct {
+		name   string           // Name of the test case
+		src    string           // Text written to the Writer
+		want   string           // Expected output
+		format tabwriter.Format // Format under test
+		flags  uint             // Writer flags
+	}{
+		{
+			name:   "csv",
+			src:    table,
+			format: tabwriter.CSV,
+			want:   "name,status\nfoo,ok\nbar,failed\n",
+		},
+		{
+			name:   "csv quoting",
+			src:    "a,b\tsay \"hi\"\n",
+			format: tabwriter.CSV,
+			want:   "\"a,b\",\"say \"\"hi\"\"\"\n",
+		},
+		{
+			name:   "tsv",
+			src:    table,
+			format: tabwriter.TSV,
+			want:   "name\tstatus\nfoo\tok\nbar\tfailed\n",
+		},
+		{
+			name:   "json",
+			src:    table,
+			format: tabwriter.JSON,
+			want:   `[{"name":"foo","status":"ok"},{"name":"bar","status":"failed"}]` + "\n",
+		},
+		{
+			name:   "json ragged",
+			src:    "a\tb\n1\n1\t2\t3\n",
+			format: tabwriter.JSON,
+			want:   `[{"a":"1"},{"a":"1","b":"2","2":"3"}]` + "\n",
+		},
+		{
+			name:   "json header only",
+			src:    "a\tb\n",
+			format: tabwriter.JSON,
+			want:   "[]\n",
+		},
+		{
+			name:   "markdown",
+			src:    table,
+			format: tabwriter.Markdown,
+			want:   "| name | status |\n| --- | --- |\n| foo | ok |\n| bar | failed |\n",
+		},
+		{
+			name:   "markdown right aligned",
+			src:    "a\tb\n1\n",
+			format: tabwriter.Markdown,
+			flags:  tabwriter.AlignRight,
+			want:   "| a | b |\n| ---: | ---: |\n| 1 |  |\n",
+		},
+		{
+			name:   "markdown escapes pipes",
+			src:    "a|b\tc\\d\n",
+			format: tabwriter.Markdown,
+			want:   "| a\\|b | c\\\\d |\n| --- | --- |\n",
+		},
+		{
+			name:   "hyperlinks and tabwriter escapes",
+			src:    "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x07\t\xffa\tb\xff\n",
+			format: tabwriter.CSV,
+			want:   "link,a\tb\n",
+		},
+		{
+			name:   "empty",
+			src:    "",
+			format: tabwriter.JSON,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := tabwriter.NewWriter(buf, 0, 8, 1, ' ', tt.flags)
+			w.SetFormat(tt.format)
+			w.SetHeaderStyle(hue.Underline) // Should be ignored
+
+			if _, err := io.WriteString(w, tt.src); err != nil {
+				t.Fatalf("Write returned an unexpected error: %v", err)
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	tests := []struct {
+		want   string
+		format tabwriter.Format
+	}{
+		{format: tabwriter.Text, want: "text"},
+		{format: tabwriter.CSV, want: "csv"},
+		{format: tabwriter.TSV, want: "tsv"},
+		{format: tabwriter.JSON, want: "json"},
+		{format: tabwriter.Markdown, want: "markdown"},
+		{format: tabwriter.Format(42), want: "Format(42)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.format.String(); got != tt.want {
+			t.Errorf("Format(%d).String() = %q, wanted %q", int(tt.format), got, tt.want)
+		}
+	}
+}
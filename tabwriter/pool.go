@@ -0,0 +1,71 @@
+package tabwriter
+
+import (
+	"io"
+	"sync"
+)
+
+// maxPooledBuf is the largest buffer capacity (in bytes) a [Writer] may hold on to
+// when returned to the pool, the occasional enormous table shouldn't pin its
+// memory for the life of the program.
+const maxPooledBuf = 64 << 10
+
+// pool is the pool of Writers backing NewPooledWriter and Release.
+var pool = sync.Pool{
+	New: func() any {
+		return new(Writer)
+	},
+}
+
+// Reset discards any unflushed data and resets the [Writer] to write to output,
+// retaining its configuration (from [Writer.Init] and the Set methods) and its
+// internal buffers.
+//
+// Reset allows a single Writer to render many tables without the allocations
+// of creating a new one each time.
+func (b *Writer) Reset(output io.Writer) {
+	b.output = output
+	b.row = 0
+	b.sampled = false
+	b.reset()
+}
+
+// NewPooledWriter is like [NewWriter] but takes the [Writer] from a shared
+// pool, reusing the internal buffers of a previously released Writer where
+// possible.
+//
+// Callers should call [Writer.Release] when done with the Writer (after the
+// final [Writer.Flush]) to return it to the pool:
+//
+//	w := tabwriter.NewPooledWriter(os.Stdout, 1, 8, 2, ' ', 0)
+//	defer w.Release()
+func NewPooledWriter( //nolint: revive // This is as per NewWriter
+	output io.Writer,
+	minwidth, tabwidth, padding int,
+	padchar byte,
+	flags uint,
+) *Writer {
+	w, ok := pool.Get().(*Writer)
+	if !ok {
+		w = new(Writer)
+	}
+
+	return w.Init(output, minwidth, tabwidth, padding, padchar, flags)
+}
+
+// Release returns the [Writer] to the pool used by [NewPooledWriter], any unflushed
+// data is discarded.
+//
+// The Writer must not be used after calling Release.
+func (b *Writer) Release() {
+	if cap(b.buf) > maxPooledBuf {
+		// Let the GC have it, a fresh one is cheap to make
+		return
+	}
+
+	// Don't keep the caller's output or styles alive while pooled, or hand them to the next caller
+	b.resetStyles()
+	b.Reset(nil)
+
+	pool.Put(b)
+}
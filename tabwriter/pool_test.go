@@ -0,0 +1,148 @@
+package tabwriter_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tabwriter"
+)
+
+func TestReset(t *testing.T) {
+	hue.Enabled(true)
+
+	first := &bytes.Buffer{}
+	w := tabwriter.NewWriter(first, 0, 8, 1, '.', 0)
+	w.SetHeaderStyle(hue.Bold)
+
+	// Left unflushed, so should be discarded by Reset
+	io.WriteString(w, "discard\tme\nplease\tthanks") //nolint: errcheck
+
+	second := &bytes.Buffer{}
+	w.Reset(second)
+
+	io.WriteString(w, "a\tb\naaa\tb\n") //nolint: errcheck
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush returned an unexpected error: %v", err)
+	}
+
+	if first.Len() != 0 {
+		t.Errorf("original output was written to after Reset: %q", first.String())
+	}
+
+	got := strconv.Quote(second.String())
+	want := strconv.Quote("\x1b[1ma\x1b[0m...\x1b[1mb\x1b[0m\naaa.b\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestPooledWriter(t *testing.T) {
+	for i := range 3 {
+		buf := &bytes.Buffer{}
+		w := tabwriter.NewPooledWriter(buf, 0, 8, 1, '.', 0)
+
+		io.WriteString(w, "a\tb\naaa\tb\n") //nolint: errcheck
+
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush returned an unexpected error: %v", err)
+		}
+
+		// Leave something unflushed to make sure it doesn't leak into the next Writer
+		io.WriteString(w, "leftover\t") //nolint: errcheck
+		w.Release()
+
+		got := strconv.Quote(buf.String())
+		want := strconv.Quote("a...b\naaa.b\n")
+
+		if got != want {
+			t.Errorf("iteration %d\nGot:\t%v\nWanted:\t%v\n", i, got, want)
+		}
+	}
+}
+
+func TestPooledWriterStylesReleased(t *testing.T) {
+	hue.Enabled(true)
+
+	w := tabwriter.NewPooledWriter(io.Discard, 0, 8, 1, '.', 0)
+	w.SetColumnStyles(hue.Green)
+	w.SetHeaderStyle(hue.Bold)
+	w.SetStyleFunc(func(int, int, string) hue.Style { return hue.Red })
+	w.SetRenderer(&hue.Renderer{Colour: true})
+	w.Release()
+
+	// Whichever Writer the pool hands out, none of the previous caller's styles should carry over
+	buf := &bytes.Buffer{}
+	w = tabwriter.NewPooledWriter(buf, 0, 8, 1, '.', 0)
+
+	io.WriteString(w, "a\tb\naaa\tb\n") //nolint: errcheck
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush returned an unexpected error: %v", err)
+	}
+
+	w.Release()
+
+	got := strconv.Quote(buf.String())
+	want := strconv.Quote("a...b\naaa.b\n")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func BenchmarkReuse(b *testing.B) {
+	table := []byte(
+		"name\tstatus\tduration\t\n" +
+			"build\tok\t1.2s\t\n" +
+			"test\tok\t14.7s\t\n" +
+			"lint\tfailed\t3.1s\t\n",
+	)
+
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			w := tabwriter.NewWriter(io.Discard, 4, 4, 1, ' ', 0)
+			w.Write(table) //nolint: errcheck
+			w.Flush()
+		}
+	})
+
+	b.Run("init", func(b *testing.B) {
+		b.ReportAllocs()
+
+		w := new(tabwriter.Writer)
+		for b.Loop() {
+			w.Init(io.Discard, 4, 4, 1, ' ', 0)
+			w.Write(table) //nolint: errcheck
+			w.Flush()
+		}
+	})
+
+	b.Run("reset", func(b *testing.B) {
+		b.ReportAllocs()
+
+		w := tabwriter.NewWriter(io.Discard, 4, 4, 1, ' ', 0)
+		for b.Loop() {
+			w.Reset(io.Discard)
+			w.Write(table) //nolint: errcheck
+			w.Flush()
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			w := tabwriter.NewPooledWriter(io.Discard, 4, 4, 1, ' ', 0)
+			w.Write(table) //nolint: errcheck
+			w.Flush()
+			w.Release()
+		}
+	})
+}
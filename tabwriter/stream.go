@@ -0,0 +1,98 @@
+package tabwriter
+
+// SetStreaming puts the [Writer] into streaming mode, in which each line is written
+// to the output as soon as it is complete rather than being buffered until [Writer.Flush].
+// This is useful for long running commands that print rows as they arrive.
+//
+// As later lines can no longer affect earlier ones, columns are not elastic in streaming
+// mode: the width of each column is fixed up front from the first sample lines
+// (which are buffered until they are all written) and from any declared widths, which
+// set the minimum width of the text in each column by index. Either may be omitted, a
+// sample of 0 with declared widths writes every line immediately.
+//
+// A cell wider than its column overflows, pushing the rest of its line to the right.
+// If the [GrowColumns] flag is set, the column is instead widened for all subsequent
+// lines.
+//
+// Calling [Writer.Flush], or writing a '\f', writes out any buffered lines and ends the
+// current table, the next line written is measured afresh. Calling SetStreaming with a sample of 0 and no
+// widths turns streaming mode off. Streaming only applies to the [Text] format.
+func (b *Writer) SetStreaming(sample int, widths ...int) {
+	b.streaming = sample > 0 || len(widths) > 0
+	b.sample = max(sample, 0)
+	b.declared = append(b.declared[:0], widths...)
+	b.sampled = false
+}
+
+// stream writes out the complete lines buffered in streaming mode, once
+// the sample lines have been collected and measured.
+func (b *Writer) stream() {
+	if !b.sampled {
+		// The last line is the (empty) one just started
+		if len(b.lines)-1 < b.sample {
+			return
+		}
+
+		b.measure()
+	}
+
+	b.writeStream()
+	b.reset()
+}
+
+// measure fixes the column widths for streaming mode from the declared
+// widths and the buffered lines.
+func (b *Writer) measure() {
+	b.colWidths = b.colWidths[:0]
+
+	for _, width := range b.declared {
+		b.colWidths = append(b.colWidths, max(width+b.padding, b.minwidth))
+	}
+
+	for _, line := range b.lines[:min(b.sample, len(b.lines))] {
+		// The last cell per line does not belong to a column
+		for j := 0; j < len(line)-1; j++ {
+			width := max(line[j].width+b.padding, b.minwidth)
+
+			if j < len(b.colWidths) {
+				b.colWidths[j] = max(b.colWidths[j], width)
+			} else {
+				b.colWidths = append(b.colWidths, width)
+			}
+		}
+	}
+
+	b.sampled = true
+}
+
+// writeStream writes all the buffered lines using the fixed column widths.
+func (b *Writer) writeStream() {
+	pos := 0
+
+	for i, line := range b.lines {
+		// widths holds the widths for this line only, a cell may
+		// overflow its column without affecting any other line
+		b.widths = b.widths[:0]
+
+		for j := 0; j < len(line)-1; j++ {
+			width := max(line[j].width+b.padding, b.minwidth)
+
+			switch {
+			case j >= len(b.colWidths):
+				if b.flags&GrowColumns != 0 {
+					b.colWidths = append(b.colWidths, width)
+				}
+			case width > b.colWidths[j]:
+				if b.flags&GrowColumns != 0 {
+					b.colWidths[j] = width
+				}
+			default:
+				width = b.colWidths[j]
+			}
+
+			b.widths = append(b.widths, width)
+		}
+
+		pos = b.writeLines(pos, i, i+1)
+	}
+}
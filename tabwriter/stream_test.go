@@ -0,0 +1,141 @@
+package tabwriter_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue/tabwriter"
+)
+
+func TestStreaming(t *testing.T) {
+	type step struct {
+		write string // What to write
+		want  string // Everything expected in the output after the write
+	}
+
+	tests := []struct {
+		name     string // Name of the test case
+		want     string // Expected output after Flush
+		steps    []step // Writes in order, with the output expected after each
+		declared []int  // Declared column widths
+		sample   int    // Number of lines to sample
+		flags    uint   // Writer flags
+	}{
+		{
+			name:   "sample",
+			sample: 2,
+			steps: []step{
+				{write: "a\tb\tc\n", want: ""},
+				{write: "aaa\tb\tc\n", want: "a...b.c\naaa.b.c\n"},
+				{write: "aa\tbb\tc\n", want: "a...b.c\naaa.b.c\naa..bb.c\n"},
+			},
+			want: "a...b.c\naaa.b.c\naa..bb.c\n",
+		},
+		{
+			name:   "form feed ends the table",
+			sample: 2,
+			steps: []step{
+				{write: "a\tb\n", want: ""},
+				{write: "aaa\tb\f", want: "a...b\naaa.b\n"},
+				{write: "a\tb\n", want: "a...b\naaa.b\n"},
+				{write: "aa\tb\n", want: "a...b\naaa.b\na..b\naa.b\n"},
+			},
+			want: "a...b\naaa.b\na..b\naa.b\n",
+		},
+		{
+			name:     "declared",
+			declared: []int{4, 2},
+			steps: []step{
+				{write: "a\tb\tc\n", want: "a....b..c\n"},
+				{write: "partial\t", want: "a....b..c\n"},
+				{write: "b\n", want: "a....b..c\npartial.b\n"},
+			},
+			want: "a....b..c\npartial.b\n",
+		},
+		{
+			name:     "overflow",
+			declared: []int{2, 2},
+			steps: []step{
+				{write: "aaaa\tb\tc\n", want: "aaaa.b..c\n"},
+				{write: "a\tb\tc\n", want: "aaaa.b..c\na..b..c\n"},
+			},
+			want: "aaaa.b..c\na..b..c\n",
+		},
+		{
+			name:     "grow",
+			declared: []int{2, 2},
+			flags:    tabwriter.GrowColumns,
+			steps: []step{
+				{write: "aaaa\tb\tc\n", want: "aaaa.b..c\n"},
+				{write: "a\tb\tc\n", want: "aaaa.b..c\na....b..c\n"},
+			},
+			want: "aaaa.b..c\na....b..c\n",
+		},
+		{
+			name:   "flush before sample complete",
+			sample: 10,
+			steps: []step{
+				{write: "a\tb\n", want: ""},
+				{write: "aa\tb", want: ""},
+			},
+			want: "a..b\naa.b",
+		},
+		{
+			name:   "right aligned",
+			sample: 1,
+			flags:  tabwriter.AlignRight,
+			steps: []step{
+				{write: "aa\tb\t\n", want: ".aa.b\n"},
+				{write: "a\tbb\t\n", want: ".aa.b\n..a.bb\n"},
+			},
+			want: ".aa.b\n..a.bb\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := tabwriter.NewWriter(buf, 0, 8, 1, '.', tt.flags)
+			w.SetStreaming(tt.sample, tt.declared...)
+
+			for i, step := range tt.steps {
+				if _, err := io.WriteString(w, step.write); err != nil {
+					t.Fatalf("Write returned an unexpected error: %v", err)
+				}
+
+				got := strconv.Quote(buf.String())
+				want := strconv.Quote(step.want)
+
+				if got != want {
+					t.Errorf("after step %d\nGot:\t%v\nWanted:\t%v\n", i, got, want)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("after Flush\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestStreamingOff(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 8, 1, '.', 0)
+	w.SetStreaming(1)
+	w.SetStreaming(0)
+
+	io.WriteString(w, "a\tb\n") //nolint: errcheck
+
+	if buf.Len() != 0 {
+		t.Fatalf("streaming mode still on, output written before Flush: %q", buf.String())
+	}
+}
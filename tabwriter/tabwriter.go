@@ -11,12 +11,21 @@
 // The hue version makes only minor adjustments to ensure that ANSI escape sequences
 // do not count towards cell width calculations and therefore, text written with hue/tabwriter
 // will format correctly with or without ANSI styles.
+//
+// It can also style cells itself as they are written out, by column, header row or a
+// caller supplied function, see [Writer.SetColumnStyles], [Writer.SetHeaderStyle]
+// and [Writer.SetStyleFunc], or re-emit the same cells as structured data such as
+// CSV or JSON, see [Writer.SetFormat].
 package tabwriter // import "go.followtheprocess.codes/hue/tabwriter"
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"unicode/utf8"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/ansi"
 )
 
 // Formatting can be controlled with these flags.
@@ -44,6 +53,11 @@ const (
 	// Print a vertical bar ('|') between columns (after formatting).
 	// Discarded columns appear as zero-width columns ("||").
 	Debug
+
+	// In streaming mode, permanently widen a column when a later cell
+	// exceeds its width, rather than letting just that cell overflow.
+	// See [Writer.SetStreaming].
+	GrowColumns
 )
 
 const escape byte = 0x1b // escape is the ANSI escape start sequence.
@@ -117,23 +131,48 @@ type cell struct {
 //
 // The Writer must buffer input internally, because proper spacing
 // of one line may depend on the cells in future lines. Clients must
-// call Flush when done calling [Writer.Write].
+// call Flush (or [Writer.Close]) when done calling [Writer.Write].
+//
+// Cells may be styled as they are written out by configuring column, header
+// or per-cell styles. Styling is applied to the cell text only (not the padding)
+// and does not affect the width calculations, so the text written to the Writer
+// can stay plain.
 type Writer struct {
-	output   io.Writer
-	buf      []byte   // collected text excluding tabs or line breaks
-	lines    [][]cell // list of lines; each line is a list of cells
-	widths   []int    // list of column widths in runes - re-used during formatting
-	cell     cell     // current incomplete cell; cell.width is up to buf[pos] excluding ignored sections
-	minwidth int
-	tabwidth int
-	padding  int
-	flags    uint
-	pos      int // buffer position up to which cell.width of incomplete cell has been computed
-	padbytes [8]byte
-	endChar  byte // terminating char of escaped sequence (Escape for escapes, '>', ';' for HTML tags/entities, or 0)
-	prev     byte // previously processed char
+	output      io.Writer
+	ctx         context.Context //nolint: containedctx // Only set for the duration of FlushContext
+	styleFunc   StyleFunc       // optional per-cell style, takes precedence over header and column styles
+	renderer    *hue.Renderer   // renders cell styles, nil uses the package wide settings
+	buf         []byte          // collected text excluding tabs or line breaks
+	lines       [][]cell        // list of lines; each line is a list of cells
+	widths      []int           // list of column widths in runes - re-used during formatting
+	colStyles   []hue.Style     // styles applied to each column by index
+	declared    []int           // declared column widths in streaming mode
+	colWidths   []int           // fixed column widths in streaming mode, including padding
+	styled      []byte          // scratch buffer for styled cell text - re-used during formatting
+	cell        cell            // current incomplete cell; cell.width is up to buf[pos] excluding ignored sections
+	sequence    ansi.Parser     // state of the ANSI escape sequence being written, while endChar is escape
+	outFormat   Format          // format of the output, Text unless set by SetFormat
+	minwidth    int
+	tabwidth    int
+	padding     int
+	flags       uint
+	pos         int // buffer position up to which cell.width of incomplete cell has been computed
+	row         int // index of the next line to be written out since the last Flush
+	sample      int // number of lines to measure column widths from in streaming mode
+	headerStyle hue.Style
+	padbytes    [8]byte
+	endChar     byte // terminating char of escaped sequence (Escape for escapes, '>', ';' for HTML tags/entities, escape for ANSI sequences, or 0)
+	streaming   bool // whether lines are written as soon as they are complete
+	sampled     bool // whether colWidths have been measured in streaming mode
 }
 
+// StyleFunc is a function returning the [hue.Style] to apply to a single cell, given
+// its row and column index and its raw text. Rows are counted from the first line
+// written after [Writer.Init] or the last call to [Writer.Flush], so row 0 is the header.
+//
+// Returning 0 (no style) falls back to the header and column styles.
+type StyleFunc func(row, col int, text string) hue.Style
+
 // addLine adds a new line.
 // flushed is a hint indicating whether the underlying writer was just flushed.
 // If so, the previous line is not likely to be a good indicator of the new line's cells.
@@ -234,11 +273,65 @@ func (b *Writer) Init( //nolint: revive // This is as per text/tabwriter
 
 	b.flags = flags
 
+	b.resetStyles()
+	b.row = 0
+	b.outFormat = Text
+	b.streaming = false
+	b.sampled = false
+	b.sample = 0
+	b.declared = b.declared[:0]
+
 	b.reset()
 
 	return b
 }
 
+// resetStyles removes every style set by the Set methods, and the renderer for them.
+func (b *Writer) resetStyles() {
+	b.colStyles = b.colStyles[:0]
+	b.headerStyle = 0
+	b.styleFunc = nil
+	b.renderer = nil
+}
+
+// SetColumnStyles sets the styles applied to the cells of each column when the
+// [Writer] is flushed, styles[0] applies to the first column, styles[1] to the second
+// and so on. Columns without a corresponding style, or with a style of 0, are written
+// unstyled.
+//
+// Calling SetColumnStyles with no arguments removes any column styles.
+func (b *Writer) SetColumnStyles(styles ...hue.Style) {
+	b.colStyles = append(b.colStyles[:0], styles...)
+}
+
+// SetHeaderStyle sets the style applied to every cell in the first row written after
+// [Writer.Init] or the last call to [Writer.Flush]. It takes precedence over any
+// column styles.
+//
+// A style of 0 removes the header style.
+func (b *Writer) SetHeaderStyle(style hue.Style) {
+	b.headerStyle = style
+}
+
+// SetStyleFunc sets a function to be called for every non-empty cell when the [Writer]
+// is flushed, allowing conditional formatting based on a cell's position or content,
+// e.g. colouring failing statuses red. A non-zero style returned from fn takes precedence
+// over both the header and column styles.
+//
+// Passing a nil fn removes any previously set function.
+func (b *Writer) SetStyleFunc(fn StyleFunc) {
+	b.styleFunc = fn
+}
+
+// SetRenderer sets the [hue.Renderer] used to apply the header, column and
+// [StyleFunc] styles when the [Writer] is flushed, rather than the package wide
+// settings, e.g. to write styled and plain tables at the same time.
+//
+// Passing a nil renderer restores the package wide settings.
+func (b *Writer) SetRenderer(renderer *hue.Renderer) {
+	b.renderer = renderer
+}
+
 // local error wrapper so we can distinguish errors we want to return
 // as errors from genuine panics (which we don't want to return as errors).
 type osError struct {
@@ -256,6 +349,44 @@ func (b *Writer) write0(buf []byte) {
 	}
 }
 
+// writeCell writes the text of the cell at row, col, styled according to
+// the styles configured on the Writer.
+func (b *Writer) writeCell(row, col int, text []byte) {
+	style := b.cellStyle(row, col, text)
+	if style == 0 {
+		b.write0(text)
+		return
+	}
+
+	if b.renderer != nil {
+		b.styled = b.renderer.AppendText(b.styled[:0], style, text)
+	} else {
+		b.styled = style.AppendText(b.styled[:0], text)
+	}
+
+	b.write0(b.styled)
+}
+
+// cellStyle returns the style to apply to the cell at row, col, or 0 if
+// the cell should not be styled.
+func (b *Writer) cellStyle(row, col int, text []byte) hue.Style {
+	if b.styleFunc != nil {
+		if style := b.styleFunc(row, col, string(text)); style != 0 {
+			return style
+		}
+	}
+
+	if row == 0 && b.headerStyle != 0 {
+		return b.headerStyle
+	}
+
+	if col < len(b.colStyles) {
+		return b.colStyles[col]
+	}
+
+	return 0
+}
+
 func (b *Writer) writeN(src []byte, n int) {
 	for n > len(src) {
 		b.write0(src)
@@ -299,6 +430,8 @@ func (b *Writer) writeLines(pos0, line0, line1 int) (pos int) {
 	pos = pos0
 
 	for i := line0; i < line1; i++ {
+		b.checkContext()
+
 		line := b.lines[i]
 
 		// if TabIndent is set, use tabs to pad leading empty cells
@@ -320,7 +453,7 @@ func (b *Writer) writeLines(pos0, line0, line1 int) (pos int) {
 				useTabs = false
 
 				if b.flags&AlignRight == 0 { // align left
-					b.write0(b.buf[pos : pos+c.size])
+					b.writeCell(b.row, j, b.buf[pos:pos+c.size])
 					pos += c.size
 
 					if j < len(b.widths) {
@@ -331,7 +464,7 @@ func (b *Writer) writeLines(pos0, line0, line1 int) (pos int) {
 						b.writePadding(c.width, b.widths[j], false)
 					}
 
-					b.write0(b.buf[pos : pos+c.size])
+					b.writeCell(b.row, j, b.buf[pos:pos+c.size])
 					pos += c.size
 				}
 			}
@@ -345,6 +478,7 @@ func (b *Writer) writeLines(pos0, line0, line1 int) (pos int) {
 		} else {
 			// not the last line - write newline
 			b.write0(newline)
+			b.row++
 		}
 	}
 
@@ -444,8 +578,10 @@ func (b *Writer) startEscape(ch byte) {
 		b.endChar = '>'
 	case '&':
 		b.endChar = ';'
-	case '[':
-		b.endChar = 'm'
+	case escape:
+		b.endChar = escape
+		b.sequence = ansi.Parser{}
+		b.sequence.WriteByte(escape) //nolint: errcheck // Parsing never fails
 	}
 }
 
@@ -462,8 +598,7 @@ func (b *Writer) endEscape() {
 			b.cell.width -= 2 // don't count the Escape chars
 		}
 	case '>': // tag of zero width
-	case 'm': // ANSI escape code of zero width
-		b.cell.width-- // Don't count escape char
+	case escape: // ANSI escape sequence of zero width
 	case ';':
 		b.cell.width++ // entity, count as one rune
 	}
@@ -505,7 +640,46 @@ func (b *Writer) handlePanic(err *error, op string) {
 // incomplete escape sequence at the end is considered
 // complete for formatting purposes.
 func (b *Writer) Flush() error {
-	return b.flush()
+	err := b.flush()
+	b.row = 0
+
+	return err
+}
+
+// FlushContext is like [Writer.Flush] but stops writing to the output if ctx is
+// cancelled, returning an error wrapping ctx.Err(). Lines written before the
+// cancellation are left in the output and any remaining buffered data is discarded.
+//
+// The context is checked before each line is written, so a table with many lines
+// can be abandoned part way through, e.g. when the request it was rendering for goes away.
+func (b *Writer) FlushContext(ctx context.Context) error {
+	b.ctx = ctx
+	defer func() { b.ctx = nil }()
+
+	return b.Flush()
+}
+
+// Close flushes the [Writer], making it an [io.WriteCloser] so that it may be
+// used as:
+//
+//	w := tabwriter.NewWriter(os.Stdout, 1, 8, 2, ' ', 0)
+//	defer w.Close()
+//
+// Close does not close the underlying output.
+func (b *Writer) Close() error {
+	return b.Flush()
+}
+
+// checkContext aborts the current flush by way of an osError panic if
+// the context passed to FlushContext has been cancelled.
+func (b *Writer) checkContext() {
+	if b.ctx == nil {
+		return
+	}
+
+	if err := b.ctx.Err(); err != nil {
+		panic(osError{fmt.Errorf("tabwriter: flush cancelled: %w", err)})
+	}
 }
 
 // flush is the internal version of Flush, with a named return value which we
@@ -532,7 +706,21 @@ func (b *Writer) flushNoDefers() {
 	}
 
 	// format contents of buffer
-	b.format(0, 0, len(b.lines))
+	switch {
+	case b.outFormat != Text:
+		b.encode()
+	case b.streaming:
+		if !b.sampled {
+			b.measure()
+		}
+
+		b.writeStream()
+
+		b.sampled = false // the next table is measured afresh
+	default:
+		b.format(0, 0, len(b.lines))
+	}
+
 	b.reset()
 }
 
@@ -548,6 +736,30 @@ func (b *Writer) Write(buf []byte) (n int, err error) {
 	n = 0
 
 	for i, ch := range buf {
+		if b.endChar == escape {
+			// inside ANSI escape sequence, which the parser knows the end of
+			b.sequence.WriteByte(ch) //nolint: errcheck // Parsing never fails
+
+			if b.sequence.State() != ansi.StateGround {
+				continue
+			}
+
+			if ch < utf8.RuneSelf {
+				b.append(buf[n : i+1])
+				n = i + 1 // ch consumed
+
+				b.endEscape()
+
+				continue
+			}
+
+			// Text can't be part of a sequence so ends it, ch is handled as normal below
+			b.append(buf[n:i])
+			n = i
+
+			b.endEscape()
+		}
+
 		if b.endChar == 0 {
 			// outside escape
 			switch ch {
@@ -563,18 +775,29 @@ func (b *Writer) Write(buf []byte) (n int, err error) {
 					// terminate line
 					b.addLine(ch == '\f')
 
-					if ch == '\f' || ncells == 1 {
-						// A '\f' always forces a flush. Otherwise, if the previous
-						// line has only one cell which does not have an impact on
-						// the formatting of the following lines (the last cell per
-						// line is ignored by format()), thus we can flush the
-						// Writer contents.
+					// Structured formats need the whole table so are only
+					// ever written on an explicit Flush.
+					switch {
+					case b.outFormat != Text:
+					case ch == '\f':
+						// A '\f' always forces a flush, ending the table. In
+						// streaming mode the next table is measured afresh.
 						b.flushNoDefers()
 
-						if ch == '\f' && b.flags&Debug != 0 {
+						if b.flags&Debug != 0 {
 							// indicate section break
 							b.write0(hbar)
 						}
+					case b.streaming:
+						// Column widths are fixed (or being sampled), so
+						// complete lines can be written straight away
+						b.stream()
+					case ncells == 1:
+						// If the previous line has only one cell which does not
+						// have an impact on the formatting of the following lines
+						// (the last cell per line is ignored by format()), thus we
+						// can flush the Writer contents.
+						b.flushNoDefers()
 					}
 				}
 
@@ -601,15 +824,14 @@ func (b *Writer) Write(buf []byte) (n int, err error) {
 
 					b.startEscape(ch)
 				}
-			case '[':
-				if b.prev == escape {
-					b.append(buf[n:i])
-					b.updateWidth()
+			case escape:
+				// start of an ANSI escape sequence e.g. a style or hyperlink
+				b.append(buf[n:i])
+				b.updateWidth()
 
-					n = i
+				n = i
 
-					b.startEscape(ch)
-				}
+				b.startEscape(escape)
 			}
 		} else if ch == b.endChar {
 			// inside escape
@@ -624,8 +846,6 @@ func (b *Writer) Write(buf []byte) (n int, err error) {
 
 			b.endEscape()
 		}
-
-		b.prev = ch
 	}
 
 	// append leftover text
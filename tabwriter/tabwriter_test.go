@@ -6,6 +6,8 @@ package tabwriter_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -619,6 +621,20 @@ var tests = []struct {
 		expected: "\x1b[93;41ma\x1b[0m.......b",
 	},
 
+	{
+		testname: "15a hyperlink",
+		minwidth: 4, tabwidth: 0, padding: 0, padchar: '.', flags: 0,
+		src:      "\x1b]8;;https://go.dev\x1b\\a\x1b]8;;\x07\t\tb",
+		expected: "\x1b]8;;https://go.dev\x1b\\a\x1b]8;;\x07.......b",
+	},
+
+	{
+		testname: "15a cursor",
+		minwidth: 4, tabwidth: 0, padding: 0, padchar: '.', flags: 0,
+		src:      "\x1b[2K\x1b7a\x1b[?25l\t\tb",
+		expected: "\x1b[2K\x1b7a\x1b[?25l.......b",
+	},
+
 	{
 		testname: "15b",
 		minwidth: 4, tabwidth: 0, padding: 0, padchar: '.', flags: tabwriter.DiscardEmptyColumns,
@@ -782,6 +798,203 @@ func TestPanicDuringWrite(t *testing.T) {
 	t.Errorf("failed to panic during Write")
 }
 
+func TestStyles(t *testing.T) {
+	hue.Enabled(true)
+
+	tests := []struct {
+		styleFunc tabwriter.StyleFunc // Per-cell style func, if any
+		renderer  *hue.Renderer       // Renderer for the styles, if any
+		name      string              // Name of the test case
+		src       string              // Text written to the Writer
+		want      string              // Expected output
+		columns   []hue.Style         // Column styles
+		header    hue.Style           // Header style
+	}{
+		{
+			name: "no styles",
+			src:  "a\tbb\tc\naaa\tb\tc\n",
+			want: "a   bb c\naaa b  c\n",
+		},
+		{
+			name:    "columns",
+			src:     "a\tbb\tc\naaa\tb\tc\n",
+			columns: []hue.Style{hue.Green, 0, hue.Red},
+			want: "\x1b[32ma\x1b[0m   bb \x1b[31mc\x1b[0m\n" +
+				"\x1b[32maaa\x1b[0m b  \x1b[31mc\x1b[0m\n",
+		},
+		{
+			name:    "header",
+			src:     "a\tbb\tc\naaa\tb\tc\n",
+			header:  hue.Bold,
+			columns: []hue.Style{hue.Green},
+			want: "\x1b[1ma\x1b[0m   \x1b[1mbb\x1b[0m \x1b[1mc\x1b[0m\n" +
+				"\x1b[32maaa\x1b[0m b  c\n",
+		},
+		{
+			name:    "style func",
+			src:     "name\tstatus\nfoo\tok\nbar\tfailed\n",
+			header:  hue.Bold,
+			columns: []hue.Style{hue.Cyan},
+			styleFunc: func(row, col int, text string) hue.Style {
+				if row > 0 && col == 1 && text == "failed" {
+					return hue.Red
+				}
+				return 0
+			},
+			want: "\x1b[1mname\x1b[0m \x1b[1mstatus\x1b[0m\n" +
+				"\x1b[36mfoo\x1b[0m  ok\n" +
+				"\x1b[36mbar\x1b[0m  \x1b[31mfailed\x1b[0m\n",
+		},
+		{
+			name:    "empty cells are not styled",
+			src:     "\tb\tc\n",
+			columns: []hue.Style{hue.Green, hue.Green, hue.Green},
+			want:    " \x1b[32mb\x1b[0m \x1b[32mc\x1b[0m\n",
+		},
+		{
+			name:     "plain renderer",
+			src:      "a\tbb\tc\naaa\tb\tc\n",
+			columns:  []hue.Style{hue.Green, 0, hue.Red},
+			header:   hue.Bold,
+			renderer: &hue.Renderer{},
+			want:     "a   bb c\naaa b  c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := tabwriter.NewWriter(buf, 0, 8, 1, ' ', 0)
+			w.SetColumnStyles(tt.columns...)
+			w.SetHeaderStyle(tt.header)
+			w.SetStyleFunc(tt.styleFunc)
+			w.SetRenderer(tt.renderer)
+
+			if _, err := io.WriteString(w, tt.src); err != nil {
+				t.Fatalf("Write returned an unexpected error: %v", err)
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestStylesHeaderResetsOnFlush(t *testing.T) {
+	hue.Enabled(true)
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 8, 1, ' ', 0)
+	w.SetHeaderStyle(hue.Bold)
+
+	for range 2 {
+		io.WriteString(w, "a\tb\nc\td\n") //nolint: errcheck
+		w.Flush()
+	}
+
+	want := strconv.Quote(
+		"\x1b[1ma\x1b[0m \x1b[1mb\x1b[0m\nc d\n" +
+			"\x1b[1ma\x1b[0m \x1b[1mb\x1b[0m\nc d\n",
+	)
+	got := strconv.Quote(buf.String())
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+func TestClose(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	var w io.WriteCloser = tabwriter.NewWriter(buf, 0, 8, 1, '.', 0)
+
+	io.WriteString(w, "a\tb\naaa\tb") //nolint: errcheck
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned an unexpected error: %v", err)
+	}
+
+	got := strconv.Quote(buf.String())
+	want := strconv.Quote("a...b\naaa.b")
+
+	if got != want {
+		t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+	}
+}
+
+// cancelWriter is an io.Writer that cancels a context after n writes.
+type cancelWriter struct {
+	buf    bytes.Buffer
+	cancel context.CancelFunc
+	n      int
+}
+
+func (c *cancelWriter) Write(p []byte) (int, error) {
+	c.n--
+	if c.n == 0 {
+		c.cancel()
+	}
+
+	return c.buf.Write(p)
+}
+
+func TestFlushContext(t *testing.T) {
+	t.Run("not cancelled", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		w := tabwriter.NewWriter(buf, 0, 8, 1, '.', 0)
+
+		io.WriteString(w, "a\tb\naaa\tb\n") //nolint: errcheck
+
+		if err := w.FlushContext(t.Context()); err != nil {
+			t.Fatalf("FlushContext returned an unexpected error: %v", err)
+		}
+
+		if got, want := buf.String(), "a...b\naaa.b\n"; got != want {
+			t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, want)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		// Cancel on the 4th write: "a", "..", "b", "\n" i.e. after the first line
+		out := &cancelWriter{cancel: cancel, n: 4}
+		w := tabwriter.NewWriter(out, 0, 8, 1, '.', 0)
+
+		io.WriteString(w, "a\tb\naaa\tb\naa\tb\n") //nolint: errcheck
+
+		err := w.FlushContext(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("FlushContext returned %v, wanted an error wrapping context.Canceled", err)
+		}
+
+		if got, want := out.buf.String(), "a...b\n"; got != want {
+			t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, want)
+		}
+
+		// The Writer should be usable again afterwards
+		out.buf.Reset()
+		io.WriteString(w, "a\tb\n") //nolint: errcheck
+
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush after cancellation returned an unexpected error: %v", err)
+		}
+
+		if got, want := out.buf.String(), "a.b\n"; got != want {
+			t.Errorf("\nGot:\t%q\nWanted:\t%q\n", got, want)
+		}
+	})
+}
+
 func TestVisual(t *testing.T) {
 	hue.Enabled(true) // go test buffers output so autodetection disabled colour
 
@@ -0,0 +1,63 @@
+package hue
+
+// OkabeIto is the Okabe-Ito palette, 8 colours chosen to remain distinguishable to people
+// with any of the common types of colour blindness.
+//
+// See https://jfly.uni-koeln.de/color/.
+var OkabeIto = struct {
+	Black         RGB
+	Orange        RGB
+	SkyBlue       RGB
+	BluishGreen   RGB
+	Yellow        RGB
+	Blue          RGB
+	Vermillion    RGB
+	ReddishPurple RGB
+}{
+	Black:         RGB{R: 0x00, G: 0x00, B: 0x00},
+	Orange:        RGB{R: 0xe6, G: 0x9f, B: 0x00},
+	SkyBlue:       RGB{R: 0x56, G: 0xb4, B: 0xe9},
+	BluishGreen:   RGB{R: 0x00, G: 0x9e, B: 0x73},
+	Yellow:        RGB{R: 0xf0, G: 0xe4, B: 0x42},
+	Blue:          RGB{R: 0x00, G: 0x72, B: 0xb2},
+	Vermillion:    RGB{R: 0xd5, G: 0x5e, B: 0x00},
+	ReddishPurple: RGB{R: 0xcc, G: 0x79, B: 0xa7},
+}
+
+// Theme is a set of colours for the common kinds of status a CLI reports, allowing
+// the choice of colours to be made once (and swapped for an accessible one) rather than
+// at every call site:
+//
+//	theme := hue.OkabeItoTheme
+//	fmt.Println(theme.Success.Text("ok"), theme.Failure.Text("failed"))
+type Theme struct {
+	Success RGB // Something worked e.g. a passing test
+	Failure RGB // Something went wrong e.g. an error
+	Warning RGB // Something may need attention
+	Info    RGB // Neutral, informational output
+	Muted   RGB // De-emphasised output e.g. timestamps or hints
+}
+
+var (
+	// OkabeItoTheme is a [Theme] built from the [OkabeIto] palette. Success and Failure are
+	// a bluish green and a vermillion, which unlike a plain green and red differ in lightness
+	// as well as hue so remain distinct under every type of colour blindness.
+	OkabeItoTheme = Theme{
+		Success: OkabeIto.BluishGreen,
+		Failure: OkabeIto.Vermillion,
+		Warning: OkabeIto.Yellow,
+		Info:    OkabeIto.SkyBlue,
+		Muted:   RGB{R: 0x99, G: 0x99, B: 0x99},
+	}
+
+	// BlueOrangeTheme is a [Theme] that avoids red and green entirely, using blue for
+	// Success and orange for Failure, for the strongest possible contrast between them
+	// under red-green colour blindness.
+	BlueOrangeTheme = Theme{
+		Success: OkabeIto.Blue,
+		Failure: OkabeIto.Orange,
+		Warning: OkabeIto.Yellow,
+		Info:    OkabeIto.SkyBlue,
+		Muted:   RGB{R: 0x99, G: 0x99, B: 0x99},
+	}
+)
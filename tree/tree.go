@@ -0,0 +1,239 @@
+// Package tree renders hierarchical data, such as dependency or directory trees, as
+// text with connecting lines drawn between the nodes.
+//
+// Labels and connectors may be styled with [hue.Style], and each node may carry a
+// trailing annotation (e.g. a version or file size) which is aligned into a column
+// using [tabwriter] so that styled and unstyled annotations line up correctly:
+//
+//	root := tree.New("hue",
+//		tree.New("tabwriter"),
+//		tree.New("golang.org/x/term", tree.New("golang.org/x/sys")),
+//	)
+//	tree.Renderer{Branch: hue.Bold}.Render(os.Stdout, root)
+//
+// Produces:
+//
+//	hue
+//	├── tabwriter
+//	└── golang.org/x/term
+//	    └── golang.org/x/sys
+package tree // import "go.followtheprocess.codes/hue/tree"
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tabwriter"
+)
+
+// padding is the minimum number of spaces between the widest label and its annotation.
+const padding = 2
+
+// Glyphs is the set of connectors used to draw a tree.
+type Glyphs struct {
+	Branch    string // Connects a node that has siblings after it e.g. "├── "
+	Last      string // Connects the last of a set of siblings e.g. "└── "
+	Vertical  string // Continues a branch past a node's children e.g. "│   "
+	Space     string // Indents beneath the last of a set of siblings e.g. "    "
+	Collapsed string // Shown with a count after the label of a node whose children are hidden e.g. "…"
+}
+
+var (
+	// Unicode draws trees with Unicode box drawing characters, it is the default.
+	Unicode = Glyphs{
+		Branch:    "├── ",
+		Last:      "└── ",
+		Vertical:  "│   ",
+		Space:     "    ",
+		Collapsed: "…",
+	}
+
+	// ASCII draws trees with plain ASCII characters, for terminals or fonts
+	// that don't support box drawing characters.
+	ASCII = Glyphs{
+		Branch:    "|-- ",
+		Last:      "`-- ",
+		Vertical:  "|   ",
+		Space:     "    ",
+		Collapsed: "...",
+	}
+)
+
+// Node is a single node in a tree.
+//
+// Labels and annotations must not contain tabs or newlines.
+type Node struct {
+	Label      string    // The text shown for the node
+	Annotation string    // Optional trailing text, aligned with the other annotations in the tree
+	Children   []*Node   // The node's children, if any
+	Style      hue.Style // Style for the label, overriding the Renderer's Branch or Leaf style
+	Collapsed  bool      // Hide the node's children, showing the Collapsed glyph instead
+}
+
+// New returns a new [Node] with the given label and children.
+func New(label string, children ...*Node) *Node {
+	return &Node{Label: label, Children: children}
+}
+
+// Add appends children to the node, returning the node to allow chaining.
+func (n *Node) Add(children ...*Node) *Node {
+	n.Children = append(n.Children, children...)
+	return n
+}
+
+// String implements [fmt.Stringer] for a [Node], rendering the tree below
+// it with the zero value [Renderer].
+func (n *Node) String() string {
+	s := &strings.Builder{}
+	Renderer{}.Render(s, n) //nolint: errcheck // Writes to a strings.Builder cannot fail
+
+	return s.String()
+}
+
+// Renderer renders a tree of [Node]s. The zero value is ready to use and draws
+// an unstyled tree with the [Unicode] glyphs.
+type Renderer struct {
+	Renderer   *hue.Renderer // Renders the styles, nil uses the package wide settings
+	Glyphs     Glyphs        // Connectors to draw the tree with, defaults to Unicode
+	Branch     hue.Style     // Style for the labels of nodes with children
+	Leaf       hue.Style     // Style for the labels of nodes without children
+	Connector  hue.Style     // Style for the connecting lines and the Collapsed glyph
+	Annotation hue.Style     // Style for annotations
+	MaxDepth   int           // Collapse nodes at this depth (the root is depth 0), 0 means no limit
+}
+
+// Render writes the tree rooted at root to w.
+func (r Renderer) Render(w io.Writer, root *Node) error {
+	if root == nil {
+		return nil
+	}
+
+	if r.Glyphs == (Glyphs{}) {
+		r.Glyphs = Unicode
+	}
+
+	if r.Renderer == nil {
+		renderer := hue.DefaultRenderer()
+		r.Renderer = &renderer
+	}
+
+	// Every label ends its cell when any node is annotated, so that all the annotations
+	// are in one column rather than split apart by lines without one. The padding this
+	// leaves after the unannotated labels is trimmed once the columns are aligned.
+	annotated := r.annotated(root, 0)
+
+	out := w
+	padded := &bytes.Buffer{}
+
+	if annotated {
+		out = padded
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 8, padding, ' ', 0)
+
+	buf := r.appendNode(nil, root, "", 0, false, annotated)
+	if _, err := tw.Write(buf); err != nil {
+		return err
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if !annotated {
+		return nil
+	}
+
+	trimmed := make([]byte, 0, padded.Len())
+	for line := range bytes.Lines(padded.Bytes()) {
+		trimmed = append(trimmed, bytes.TrimRight(bytes.TrimSuffix(line, []byte("\n")), " ")...)
+		trimmed = append(trimmed, '\n')
+	}
+
+	_, err := w.Write(trimmed)
+
+	return err
+}
+
+// annotated reports whether n, or any of its descendants shown by the renderer, has an annotation.
+func (r Renderer) annotated(n *Node, depth int) bool {
+	if n.Annotation != "" {
+		return true
+	}
+
+	if n.Collapsed || (r.MaxDepth > 0 && depth >= r.MaxDepth) {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if r.annotated(child, depth+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendNode appends the line for n, and those of its children, to dst.
+//
+// prefix is the (unstyled) indentation inherited from n's ancestors and last
+// reports whether n is the last of its siblings. The root, at depth 0, has
+// neither. If annotated, the label is always followed by a tab to end its cell.
+func (r Renderer) appendNode(dst []byte, n *Node, prefix string, depth int, last, annotated bool) []byte {
+	if depth > 0 {
+		connector := r.Glyphs.Branch
+		if last {
+			connector = r.Glyphs.Last
+		}
+
+		dst = r.Renderer.AppendString(dst, r.Connector, prefix+connector)
+	}
+
+	style := n.Style
+	if style == 0 {
+		style = r.Leaf
+		if len(n.Children) != 0 {
+			style = r.Branch
+		}
+	}
+
+	dst = r.Renderer.AppendString(dst, style, n.Label)
+
+	collapsed := n.Collapsed || (r.MaxDepth > 0 && depth >= r.MaxDepth)
+	if collapsed && len(n.Children) != 0 {
+		dst = append(dst, ' ')
+		dst = r.Renderer.AppendString(dst, r.Connector, r.Glyphs.Collapsed+" +"+strconv.Itoa(len(n.Children)))
+	}
+
+	if annotated {
+		dst = append(dst, '\t')
+	}
+
+	if n.Annotation != "" {
+		dst = r.Renderer.AppendString(dst, r.Annotation, n.Annotation)
+	}
+
+	dst = append(dst, '\n')
+
+	if collapsed {
+		return dst
+	}
+
+	// The root has no connector so its children don't need any extra indent
+	if depth > 0 {
+		if last {
+			prefix += r.Glyphs.Space
+		} else {
+			prefix += r.Glyphs.Vertical
+		}
+	}
+
+	for i, child := range n.Children {
+		dst = r.appendNode(dst, child, prefix, depth+1, i == len(n.Children)-1, annotated)
+	}
+
+	return dst
+}
@@ -0,0 +1,216 @@
+package tree_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+	"go.followtheprocess.codes/hue/tree"
+)
+
+func TestRender(t *testing.T) {
+	deps := func() *tree.Node {
+		return tree.New("app",
+			tree.New("cli", tree.New("flag"), tree.New("hue")),
+			tree.New("log", tree.New("hue")),
+		)
+	}
+
+	annotated := &tree.Node{
+		Label:      "go.mod",
+		Annotation: "v1.0.0",
+		Children: []*tree.Node{
+			{Label: "golang.org/x/term", Annotation: "v0.44.0", Children: []*tree.Node{
+				{Label: "golang.org/x/sys", Annotation: "v0.46.0"},
+			}},
+			{Label: "hue", Annotation: "v1.2.3"},
+		},
+	}
+
+	tests := []struct {
+		root     *tree.Node    // Root of the tree under test
+		name     string        // Name of the test case
+		want     string        // Expected output
+		renderer tree.Renderer // Renderer under test
+	}{
+		{
+			name: "unicode",
+			root: deps(),
+			want: "app\n" +
+				"├── cli\n" +
+				"│   ├── flag\n" +
+				"│   └── hue\n" +
+				"└── log\n" +
+				"    └── hue\n",
+		},
+		{
+			name:     "ascii",
+			root:     deps(),
+			renderer: tree.Renderer{Glyphs: tree.ASCII},
+			want: "app\n" +
+				"|-- cli\n" +
+				"|   |-- flag\n" +
+				"|   `-- hue\n" +
+				"`-- log\n" +
+				"    `-- hue\n",
+		},
+		{
+			name:     "max depth",
+			root:     deps(),
+			renderer: tree.Renderer{MaxDepth: 1},
+			want: "app\n" +
+				"├── cli … +2\n" +
+				"└── log … +1\n",
+		},
+		{
+			name: "collapsed node",
+			root: tree.New("app",
+				&tree.Node{Label: "vendor", Collapsed: true, Children: []*tree.Node{tree.New("a"), tree.New("b")}},
+				tree.New("main.go"),
+			),
+			want: "app\n" +
+				"├── vendor … +2\n" +
+				"└── main.go\n",
+		},
+		{
+			name: "annotations",
+			root: annotated,
+			want: "go.mod                    v1.0.0\n" +
+				"├── golang.org/x/term     v0.44.0\n" +
+				"│   └── golang.org/x/sys  v0.46.0\n" +
+				"└── hue                   v1.2.3\n",
+		},
+		{
+			name: "some annotated",
+			root: tree.New("go.mod",
+				&tree.Node{Label: "a", Annotation: "1.0"},
+				tree.New("bbbbbbbb"),
+				&tree.Node{Label: "c", Annotation: "2.0"},
+				&tree.Node{Label: "d", Collapsed: true, Children: []*tree.Node{{Label: "hidden", Annotation: "9.9"}}},
+			),
+			want: "go.mod\n" +
+				"├── a         1.0\n" +
+				"├── bbbbbbbb\n" +
+				"├── c         2.0\n" +
+				"└── d … +1\n",
+		},
+		{
+			name: "root only",
+			root: tree.New("lonely"),
+			want: "lonely\n",
+		},
+		{
+			name: "nil root",
+			root: nil,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hue.Enabled(false)
+
+			buf := &bytes.Buffer{}
+			if err := tt.renderer.Render(buf, tt.root); err != nil {
+				t.Fatalf("Render returned an unexpected error: %v", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("\nGot:\n%s\nWanted:\n%s\n", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderStyled(t *testing.T) {
+	hue.Enabled(true)
+
+	root := &tree.Node{
+		Label: "root",
+		Children: []*tree.Node{
+			{Label: "leaf", Annotation: "1"},
+			{Label: "special", Annotation: "22", Style: hue.Red},
+		},
+	}
+
+	renderer := tree.Renderer{
+		Branch:     hue.Bold,
+		Leaf:       hue.Green,
+		Connector:  hue.BrightBlack,
+		Annotation: hue.Cyan,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderer.Render(buf, root); err != nil {
+		t.Fatalf("Render returned an unexpected error: %v", err)
+	}
+
+	// Escapes don't count towards the width so annotations still line up
+	want := "\x1b[1mroot\x1b[0m\n" +
+		"\x1b[90m├── \x1b[0m\x1b[32mleaf\x1b[0m     \x1b[36m1\x1b[0m\n" +
+		"\x1b[90m└── \x1b[0m\x1b[31mspecial\x1b[0m  \x1b[36m22\x1b[0m\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("\nGot:\t%s\nWanted:\t%s\n", strconv.Quote(got), strconv.Quote(want))
+	}
+}
+
+func TestRenderRenderer(t *testing.T) {
+	t.Parallel()
+
+	root := tree.New("root").Add(tree.New("leaf"))
+
+	tests := []struct {
+		renderer *hue.Renderer // Renderer to use, regardless of the package wide settings
+		name     string        // Name of the test case
+		want     string        // Expected output
+	}{
+		{
+			name:     "plain",
+			renderer: &hue.Renderer{},
+			want:     "root\n└── leaf\n",
+		},
+		{
+			name:     "colour",
+			renderer: &hue.Renderer{Colour: true},
+			want:     "\x1b[1mroot\x1b[0m\n\x1b[90m└── \x1b[0m\x1b[32mleaf\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			renderer := tree.Renderer{
+				Renderer:  tt.renderer,
+				Branch:    hue.Bold,
+				Leaf:      hue.Green,
+				Connector: hue.BrightBlack,
+			}
+
+			buf := &bytes.Buffer{}
+			if err := renderer.Render(buf, root); err != nil {
+				t.Fatalf("Render returned an unexpected error: %v", err)
+			}
+
+			got := strconv.Quote(buf.String())
+			want := strconv.Quote(tt.want)
+
+			if got != want {
+				t.Errorf("\nGot:\t%v\nWanted:\t%v\n", got, want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	hue.Enabled(false)
+
+	root := tree.New("a").Add(tree.New("b"), tree.New("c"))
+
+	want := "a\n├── b\n└── c\n"
+	if got := root.String(); got != want {
+		t.Errorf("\nGot:\n%s\nWanted:\n%s\n", got, want)
+	}
+}
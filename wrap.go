@@ -0,0 +1,298 @@
+package hue
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap wraps text to lines of at most width columns, breaking on spaces.
+//
+// It is shorthand for:
+//
+//	hue.Wrapper{Width: width}.Text(text)
+//
+// See [Wrapper] for details.
+func Wrap(text string, width int) string {
+	return Wrapper{Width: width}.Text(text)
+}
+
+// Wrapper wraps text to a maximum width, breaking lines on spaces, with optional
+// prefixes and indentation. It is useful for fitting help text and error messages
+// to the width of a terminal.
+//
+// Text may be styled: ANSI escape sequences don't count towards the width of a line
+// and are never split, and any style in effect when a line is broken is reset at the
+// end of that line and re-opened at the start of the next, so the prefix and indentation
+// are never styled by accident. Likewise a [Hyperlink] is closed and re-opened, so it
+// doesn't take in the prefix and indentation either.
+//
+// Each line of the input (separated by '\n') is a paragraph: its first line is indented
+// with Indent and any further lines it is wrapped onto with Hang, which allows for hanging
+// indents e.g.
+//
+//	w := hue.Wrapper{Width: 40, Indent: "  -v, --verbose  ", Hang: strings.Repeat(" ", 17)}
+//
+// Words longer than the available width are broken across lines.
+//
+// Like [go.followtheprocess.codes/hue/tabwriter], a Wrapper assumes every rune has a width of 1,
+// text should not contain tabs.
+type Wrapper struct {
+	Prefix string // Written at the start of every line e.g. "> " or "// "
+	Indent string // Written after the Prefix on the first line of each paragraph
+	Hang   string // Written after the Prefix on the following lines of each paragraph
+	Width  int    // Maximum width of each line, including Prefix, Indent and Hang, <= 0 disables wrapping
+}
+
+// Text returns text wrapped as configured by the [Wrapper].
+func (w Wrapper) Text(text string) string {
+	state := wrapState{wrapper: w}
+
+	trailing := strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+
+	var dst []byte
+	for i, paragraph := range strings.Split(text, "\n") {
+		if i > 0 {
+			dst = append(dst, '\n')
+		}
+
+		dst = state.appendParagraph(dst, paragraph)
+	}
+
+	if trailing {
+		dst = append(dst, '\n')
+	}
+
+	return string(dst)
+}
+
+// Writer returns a [WrapWriter] that wraps everything written to it as configured
+// by the [Wrapper] before writing it to out.
+func (w Wrapper) Writer(out io.Writer) *WrapWriter {
+	return &WrapWriter{out: out, state: wrapState{wrapper: w}}
+}
+
+// WrapWriter is an [io.Writer] that wraps text written to it before passing it on to the
+// underlying writer, see [Wrapper].
+//
+// Text is buffered until a complete line has been written, callers must call
+// [WrapWriter.Flush] when done writing to ensure any final incomplete line is written.
+type WrapWriter struct {
+	out   io.Writer
+	buf   []byte // incomplete line written so far
+	dst   []byte // scratch buffer for the wrapped output, re-used between writes
+	state wrapState
+}
+
+// Write writes p to the WrapWriter, wrapping and writing any complete lines to
+// the underlying writer.
+//
+// It returns len(p) and a nil error unless the underlying writer returned an error.
+func (w *WrapWriter) Write(p []byte) (n int, err error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+
+		w.dst = w.state.appendParagraph(w.dst[:0], string(w.buf[:i]))
+		w.dst = append(w.dst, '\n')
+
+		// Shift the rest of the buffer down so it can be re-used
+		w.buf = w.buf[:copy(w.buf, w.buf[i+1:])]
+
+		if _, err := w.out.Write(w.dst); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush wraps and writes any incomplete final line to the underlying writer.
+func (w *WrapWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	w.dst = w.state.appendParagraph(w.dst[:0], string(w.buf))
+	w.buf = w.buf[:0]
+
+	_, err := w.out.Write(w.dst)
+
+	return err
+}
+
+// wrapState is the state of an in progress wrap, which carries over between paragraphs
+// (and calls to Write) as a style may be opened in one and closed in another.
+type wrapState struct {
+	wrapper Wrapper
+	line    []byte // content of the current line, excluding prefix and indentation
+	active  []byte // SGR sequences in effect
+	link    string // OSC 8 sequence opening the hyperlink in effect, if any
+	pending string // spaces after the last word on the line, written only if another word fits
+	width   int    // visible width of line
+	avail   int    // width available for line
+	first   bool   // whether the current line is the first of its paragraph
+}
+
+// appendParagraph wraps a single paragraph (a line of input without its '\n') and
+// appends the result to dst.
+func (s *wrapState) appendParagraph(dst []byte, paragraph string) []byte {
+	s.first = true
+	s.startLine()
+
+	for i := 0; i < len(paragraph); {
+		// Run of spaces
+		if paragraph[i] == ' ' {
+			j := i
+			for j < len(paragraph) && paragraph[j] == ' ' {
+				j++
+			}
+
+			if s.first && s.width == 0 {
+				// Leading space at the start of a paragraph is deliberate indentation
+				s.line = append(s.line, paragraph[i:j]...)
+				s.width += j - i
+			} else {
+				s.pending = paragraph[i:j]
+			}
+
+			i = j
+
+			continue
+		}
+
+		// Word, which may contain escape sequences
+		j := i
+		for j < len(paragraph) && paragraph[j] != ' ' {
+			if n := escapeLen(paragraph[j:]); n > 0 {
+				j += n
+			} else {
+				j++
+			}
+		}
+
+		dst = s.appendWord(dst, paragraph[i:j])
+		i = j
+	}
+
+	return s.endLine(dst)
+}
+
+// appendWord adds word to the current line, first breaking the line if it
+// doesn't fit.
+func (s *wrapState) appendWord(dst []byte, word string) []byte {
+	width := visibleWidth(word)
+
+	if s.width > 0 && s.width+len(s.pending)+width > s.avail {
+		dst = s.endLine(dst)
+		dst = append(dst, '\n')
+		s.first = false
+		s.startLine()
+	}
+
+	if s.width > 0 {
+		s.line = append(s.line, s.pending...)
+		s.width += len(s.pending)
+	}
+
+	s.pending = ""
+
+	for i := 0; i < len(word); {
+		if n := escapeLen(word[i:]); n > 0 {
+			s.track(word[i : i+n])
+			s.line = append(s.line, word[i:i+n]...)
+			i += n
+
+			continue
+		}
+
+		// Word longer than the whole line, break it wherever we have to
+		if s.width >= s.avail {
+			dst = s.endLine(dst)
+			dst = append(dst, '\n')
+			s.first = false
+			s.startLine()
+		}
+
+		_, size := utf8.DecodeRuneInString(word[i:])
+		s.line = append(s.line, word[i:i+size]...)
+		s.width++
+		i += size
+	}
+
+	return dst
+}
+
+// track updates the active styles and hyperlink with seq, a complete escape sequence.
+func (s *wrapState) track(seq string) {
+	if link, open := isHyperlink(seq); link {
+		s.link = ""
+		if open {
+			s.link = seq
+		}
+
+		return
+	}
+
+	if !isSGR(seq) {
+		return
+	}
+
+	if isReset(seq) {
+		s.active = s.active[:0]
+		return
+	}
+
+	s.active = append(s.active, seq...)
+}
+
+// startLine begins a new line, re-opening any active styles and hyperlink.
+func (s *wrapState) startLine() {
+	indent := s.wrapper.Hang
+	if s.first {
+		indent = s.wrapper.Indent
+	}
+
+	s.avail = 1<<31 - 1 // No wrapping
+	if s.wrapper.Width > 0 {
+		// Always leave room for at least one character, or we'd never finish
+		s.avail = max(s.wrapper.Width-visibleWidth(s.wrapper.Prefix)-visibleWidth(indent), 1)
+	}
+
+	s.line = append(append(s.line[:0], s.link...), s.active...)
+	s.width = 0
+	s.pending = ""
+}
+
+// endLine appends the current line, with its prefix and indentation, to dst
+// resetting any active styles and closing any hyperlink.
+func (s *wrapState) endLine(dst []byte) []byte {
+	dst = append(dst, s.wrapper.Prefix...)
+	if s.first {
+		dst = append(dst, s.wrapper.Indent...)
+	} else {
+		dst = append(dst, s.wrapper.Hang...)
+	}
+
+	if len(s.line) == len(s.link)+len(s.active) && s.width == 0 {
+		// Nothing on the line but the re-opened styles, leave it blank
+		return dst
+	}
+
+	dst = append(dst, s.line...)
+	if len(s.active) != 0 {
+		dst = append(dst, reset...)
+	}
+
+	if s.link != "" {
+		dst = append(dst, linkClose...)
+	}
+
+	return dst
+}
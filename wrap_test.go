@@ -0,0 +1,150 @@
+package hue_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"go.followtheprocess.codes/hue"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string      // Name of the test case
+		text    string      // Text to wrap
+		want    string      // Expected result
+		wrapper hue.Wrapper // Wrapper under test
+	}{
+		{
+			name:    "fits",
+			text:    "hello there",
+			wrapper: hue.Wrapper{Width: 20},
+			want:    "hello there",
+		},
+		{
+			name:    "simple",
+			text:    "the quick brown fox jumps over the lazy dog",
+			wrapper: hue.Wrapper{Width: 10},
+			want:    "the quick\nbrown fox\njumps over\nthe lazy\ndog",
+		},
+		{
+			name:    "no wrapping",
+			text:    "the quick brown fox jumps over the lazy dog",
+			wrapper: hue.Wrapper{},
+			want:    "the quick brown fox jumps over the lazy dog",
+		},
+		{
+			name:    "paragraphs and trailing newline",
+			text:    "one two three\n\nfour five\n",
+			wrapper: hue.Wrapper{Width: 8},
+			want:    "one two\nthree\n\nfour\nfive\n",
+		},
+		{
+			name:    "long word is broken",
+			text:    "a supercalifragilistic word",
+			wrapper: hue.Wrapper{Width: 8},
+			want:    "a\nsupercal\nifragili\nstic\nword",
+		},
+		{
+			name:    "spaces between words are kept",
+			text:    "a  b   c",
+			wrapper: hue.Wrapper{Width: 20},
+			want:    "a  b   c",
+		},
+		{
+			name:    "leading space is kept",
+			text:    "    indented text here",
+			wrapper: hue.Wrapper{Width: 12},
+			want:    "    indented\ntext here",
+		},
+		{
+			name:    "prefix",
+			text:    "the quick brown fox jumps",
+			wrapper: hue.Wrapper{Width: 12, Prefix: "// "},
+			want:    "// the quick\n// brown fox\n// jumps",
+		},
+		{
+			name:    "hanging indent",
+			text:    "Show verbose output including debug logs",
+			wrapper: hue.Wrapper{Width: 30, Indent: "  -v, --verbose  ", Hang: "                 "},
+			want:    "  -v, --verbose  Show verbose\n                 output\n                 including\n                 debug logs",
+		},
+		{
+			name:    "escapes don't count",
+			text:    "\x1b[1mbold\x1b[0m and \x1b[31mred\x1b[0m text",
+			wrapper: hue.Wrapper{Width: 12},
+			want:    "\x1b[1mbold\x1b[0m and \x1b[31mred\x1b[0m\ntext",
+		},
+		{
+			name:    "style is reopened on each line",
+			text:    "\x1b[31mthe quick brown fox\x1b[0m jumps",
+			wrapper: hue.Wrapper{Width: 11, Prefix: "> "},
+			want:    "> \x1b[31mthe quick\x1b[0m\n> \x1b[31mbrown fox\x1b[0m\n> jumps",
+		},
+		{
+			name:    "style across paragraphs",
+			text:    "\x1b[1;32mone\ntwo\x1b[0m three",
+			wrapper: hue.Wrapper{Width: 20},
+			want:    "\x1b[1;32mone\x1b[0m\n\x1b[1;32mtwo\x1b[0m three",
+		},
+		{
+			name:    "combined styles are all reopened",
+			text:    "\x1b[1m\x1b[4mone two\x1b[0m",
+			wrapper: hue.Wrapper{Width: 4},
+			want:    "\x1b[1m\x1b[4mone\x1b[0m\n\x1b[1m\x1b[4mtwo\x1b[0m",
+		},
+		{
+			name:    "long styled word",
+			text:    "\x1b[32mabcdefgh\x1b[0m",
+			wrapper: hue.Wrapper{Width: 3},
+			want:    "\x1b[32mabc\x1b[0m\n\x1b[32mdef\x1b[0m\n\x1b[32mgh\x1b[0m",
+		},
+		{
+			name:    "hyperlink closed and reopened",
+			text:    "\x1b]8;;https://go.dev\x1b\\go dev\x1b]8;;\x1b\\ x",
+			wrapper: hue.Wrapper{Width: 6, Prefix: "> "},
+			want: "> \x1b]8;;https://go.dev\x1b\\go\x1b]8;;\x1b\\\n" +
+				"> \x1b]8;;https://go.dev\x1b\\dev\x1b]8;;\x1b\\\n" +
+				"> x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.wrapper.Text(tt.text)
+			if got != tt.want {
+				t.Errorf("\nGot:\n%s\nWanted:\n%s\n\nGot:\t%s\nWanted:\t%s\n", got, tt.want, strconv.Quote(got), strconv.Quote(tt.want))
+			}
+
+			// The Writer should produce exactly the same thing, however the text is written
+			buf := &bytes.Buffer{}
+			w := tt.wrapper.Writer(buf)
+
+			for i := range len(tt.text) {
+				if _, err := io.WriteString(w, tt.text[i:i+1]); err != nil {
+					t.Fatalf("Write returned an unexpected error: %v", err)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush returned an unexpected error: %v", err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Writer\nGot:\t%s\nWanted:\t%s\n", strconv.Quote(got), strconv.Quote(tt.want))
+			}
+		})
+	}
+}
+
+func TestWrapFunc(t *testing.T) {
+	hue.Enabled(true)
+
+	text := hue.Green.Text("all the text is green")
+
+	want := "\x1b[32mall the\x1b[0m\n\x1b[32mtext is\x1b[0m\n\x1b[32mgreen\x1b[0m"
+	if got := hue.Wrap(text, 8); got != want {
+		t.Errorf("\nGot:\t%s\nWanted:\t%s\n", strconv.Quote(got), strconv.Quote(want))
+	}
+}